@@ -0,0 +1,182 @@
+// Package events is a publish/subscribe bus that lets integrations react
+// to what happens in the games and on the device without reaching into
+// either.
+//
+// Events are plain structs. Subscribe picks the events it is interested in
+// by type:
+//
+//	events.Subscribe(func(e events.GameStarted) {
+//		fmt.Println("now playing", e.Game)
+//	})
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is any value published on a Bus.
+type Event any
+
+// GameStarted is published when the Manager switches to a game.
+type GameStarted struct {
+	Game string
+}
+
+// ScoreChanged is published when a player's score in a game changes.
+// Players are numbered from 1; Player is 0 in single player games.
+type ScoreChanged struct {
+	Game   string
+	Player int
+	Score  int
+}
+
+// GameOver is published when a round ends. In multiplayer games it is
+// published for every player, the winner first with Won set; after a draw
+// no player has Won set. Score is the final score of Player, numbered
+// like in ScoreChanged.
+type GameOver struct {
+	Game   string
+	Player int
+	Score  int
+	Won    bool
+}
+
+// FrameFlushed is published when LED updates were queued for the device.
+// They are written to it in the background, so they may not be shown yet.
+type FrameFlushed struct {
+	Time time.Time
+	Pads int
+}
+
+// DeviceReconnected is published after the watchdog reset a stuck device
+// output. Err is set if reopening it failed.
+type DeviceReconnected struct {
+	Err error
+}
+
+// Bus delivers published events to subscribers. The zero value is ready
+// to use.
+//
+// Publishing never waits for a subscriber: each one gets its events in
+// order on a goroutine of its own, so a handler may take its time or call
+// back into the code that published the event. Events queue up while a
+// handler is busy.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[int]*subscriber
+	next        int
+}
+
+// subscriber queues the events for one handler.
+type subscriber struct {
+	accept func(Event) bool
+	fn     func(Event)
+
+	mu     sync.Mutex
+	queue  []Event
+	wake   chan struct{}
+	closed bool
+}
+
+// Default is the bus the package level functions use.
+var Default = &Bus{}
+
+// Publish delivers e to the subscribers of the Default bus.
+func Publish(e Event) {
+	Default.Publish(e)
+}
+
+// Subscribe calls fn for every event of type T published on the Default
+// bus until the returned function is called.
+func Subscribe[T Event](fn func(T)) (unsubscribe func()) {
+	return SubscribeTo(Default, fn)
+}
+
+// SubscribeTo calls fn for every event of type T published on b until
+// the returned function is called.
+func SubscribeTo[T Event](b *Bus, fn func(T)) (unsubscribe func()) {
+	return b.subscribe(
+		func(e Event) bool { _, ok := e.(T); return ok },
+		func(e Event) { fn(e.(T)) },
+	)
+}
+
+// SubscribeAll calls fn for every event published on b until the
+// returned function is called.
+func (b *Bus) SubscribeAll(fn func(Event)) (unsubscribe func()) {
+	return b.subscribe(nil, fn)
+}
+
+func (b *Bus) subscribe(accept func(Event) bool, fn func(Event)) (unsubscribe func()) {
+	s := &subscriber{accept: accept, fn: fn, wake: make(chan struct{}, 1)}
+	go s.run()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subscribers == nil {
+		b.subscribers = make(map[int]*subscriber)
+	}
+	id := b.next
+	b.next++
+	b.subscribers[id] = s
+	return func() {
+		b.mu.Lock()
+		delete(b.subscribers, id)
+		b.mu.Unlock()
+		s.close()
+	}
+}
+
+// Publish queues e for every subscriber of b and returns without waiting
+// for them.
+func (b *Bus) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, s := range b.subscribers {
+		if s.accept == nil || s.accept(e) {
+			s.push(e)
+		}
+	}
+}
+
+func (s *subscriber) push(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.queue = append(s.queue, e)
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// close stops delivery. Events still queued are dropped.
+func (s *subscriber) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		s.queue = nil
+		close(s.wake)
+	}
+}
+
+// run calls the handler for queued events until the subscriber is closed.
+func (s *subscriber) run() {
+	for range s.wake {
+		for {
+			s.mu.Lock()
+			if s.closed || len(s.queue) == 0 {
+				s.mu.Unlock()
+				break
+			}
+			e := s.queue[0]
+			s.queue = s.queue[1:]
+			s.mu.Unlock()
+			s.fn(e)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscribeTo(t *testing.T) {
+	var b Bus
+	got := make(chan ScoreChanged, 10)
+	defer SubscribeTo(&b, func(e ScoreChanged) { got <- e })()
+
+	b.Publish(GameStarted{Game: "Snake"})
+	for score := 1; score <= 3; score++ {
+		b.Publish(ScoreChanged{Game: "Snake", Score: score})
+	}
+	for want := 1; want <= 3; want++ {
+		select {
+		case e := <-got:
+			if e.Score != want {
+				t.Fatalf("got score %d, want %d", e.Score, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("score %d not delivered", want)
+		}
+	}
+}
+
+// A handler that waits for the publisher must not hold it up.
+func TestPublishDoesNotWait(t *testing.T) {
+	var b Bus
+	release := make(chan struct{})
+	done := make(chan struct{})
+	defer b.SubscribeAll(func(Event) { <-release })()
+
+	go func() {
+		b.Publish(GameStarted{})
+		b.Publish(GameStarted{})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a busy handler")
+	}
+	close(release)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	var b Bus
+	got := make(chan Event, 10)
+	unsubscribe := b.SubscribeAll(func(e Event) { got <- e })
+	unsubscribe()
+
+	b.Publish(GameStarted{})
+	select {
+	case e := <-got:
+		t.Fatalf("got %v after unsubscribing", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,59 @@
+package games
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+func init() {
+	RegisterGame(func() Game { return &Colors{} })
+}
+
+// Colors steps a pad through the palette each time it is pressed and
+// prints the index, which makes it easy to look up colour codes.
+type Colors struct {
+	mu     sync.Mutex
+	screen Screen
+	frame  launchpad.Frame
+}
+
+func (g *Colors) Name() string { return "Colors" }
+
+func (g *Colors) Start(screen Screen) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.screen = screen
+	g.frame = launchpad.Frame{}
+	g.screen.Render(g.frame)
+}
+
+func (g *Colors) Press(pos launchpad.PadPos, down bool) {
+	if !down {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	pad := g.frame.Pad(pos)
+	if pad.Color < 124 {
+		pad.Color = pad.Color + 4
+	} else {
+		pad.Color = 0
+	}
+	fmt.Printf("Color: %d\n", pad.Color)
+	g.frame.SetPad(pad)
+	g.screen.Render(g.frame)
+}
+
+// Controls captures the top row so its buttons can be coloured too.
+func (g *Colors) Controls() []Control {
+	controls := make([]Control, 8)
+	for c := range controls {
+		controls[c] = Control{launchpad.PadPos{Row: 9, Col: uint8(c + 1)}, "Next colour"}
+	}
+	return controls
+}
+
+func (g *Colors) Stop() {}
@@ -0,0 +1,179 @@
+package games
+
+import (
+	"sync"
+	"time"
+
+	"github.com/codeneuss/LaunchPadStreamer/events"
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+func init() {
+	RegisterGame(func() Game { return &ConnectFour{} })
+}
+
+const (
+	c4Cols = 7
+	c4Rows = 6
+	// c4DropRow is where a piece starts falling, just above the board.
+	c4DropRow = c4Rows + 1
+	// c4TurnRow shows whose turn it is.
+	c4TurnRow = 8
+
+	c4DropInterval = 60 * time.Millisecond
+)
+
+// c4Colors are the piece colours of the two players, indexed by player.
+var c4Colors = [3]uint8{launchpad.ColorOff, launchpad.ColorRed, launchpad.ColorYellow}
+
+// ConnectFour is the two player game on a 7x6 board in the bottom left
+// of the grid. Pressing any pad of a column drops a piece into it; the
+// first player to line up four wins and their line blinks. The next press
+// after a win or a full board starts a new round.
+type ConnectFour struct {
+	ticker *Ticker
+
+	mu     sync.Mutex
+	screen Screen
+	board  [c4Rows][c4Cols]int // 0 for empty, else the player
+	player int
+	// The piece being dropped, if dropping is set.
+	dropping         bool
+	dropCol, dropRow int
+	dropTarget       int
+	winLine          []launchpad.PadPos
+	finished         bool
+}
+
+func (g *ConnectFour) Name() string { return "Connect Four" }
+
+func (g *ConnectFour) Start(screen Screen) {
+	g.mu.Lock()
+	g.screen = screen
+	g.reset()
+	g.draw()
+	g.mu.Unlock()
+
+	g.ticker = StartTicker(c4DropInterval, g.tick)
+}
+
+func (g *ConnectFour) Press(pos launchpad.PadPos, down bool) {
+	if !down || !onGrid(pos) {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.finished {
+		g.reset()
+		g.draw()
+		return
+	}
+	col := int(pos.Col) - 1
+	if g.dropping || col >= c4Cols || g.board[c4Rows-1][col] != 0 {
+		return
+	}
+
+	g.dropping = true
+	g.dropCol, g.dropRow = col, c4DropRow-1
+	for g.dropTarget = 0; g.board[g.dropTarget][col] != 0; g.dropTarget++ {
+	}
+	g.draw()
+}
+
+func (g *ConnectFour) Stop() {
+	g.ticker.Stop()
+}
+
+func (g *ConnectFour) reset() {
+	g.board = [c4Rows][c4Cols]int{}
+	g.player = 1
+	g.dropping = false
+	g.winLine = nil
+	g.finished = false
+}
+
+// tick moves a falling piece down one row and settles it at the bottom.
+func (g *ConnectFour) tick() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.dropping {
+		return
+	}
+	if g.dropRow > g.dropTarget {
+		g.dropRow--
+		g.draw()
+		return
+	}
+
+	g.dropping = false
+	g.board[g.dropTarget][g.dropCol] = g.player
+	if line := g.line(g.dropTarget, g.dropCol); line != nil {
+		g.winLine = line
+		g.finished = true
+		events.Publish(events.GameOver{Game: g.Name(), Player: g.player, Won: true})
+		events.Publish(events.GameOver{Game: g.Name(), Player: 3 - g.player})
+	} else if g.full() {
+		g.finished = true
+		events.Publish(events.GameOver{Game: g.Name(), Player: 1})
+		events.Publish(events.GameOver{Game: g.Name(), Player: 2})
+	} else {
+		g.player = 3 - g.player
+	}
+	g.draw()
+}
+
+// line returns the four or more pieces in a row through the piece at row,
+// col, or nil if there are fewer.
+func (g *ConnectFour) line(row, col int) []launchpad.PadPos {
+	player := g.board[row][col]
+	for _, d := range [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}} {
+		line := []launchpad.PadPos{c4Pos(row, col)}
+		for _, sign := range []int{1, -1} {
+			r, c := row+sign*d[0], col+sign*d[1]
+			for r >= 0 && r < c4Rows && c >= 0 && c < c4Cols && g.board[r][c] == player {
+				line = append(line, c4Pos(r, c))
+				r, c = r+sign*d[0], c+sign*d[1]
+			}
+		}
+		if len(line) >= 4 {
+			return line
+		}
+	}
+	return nil
+}
+
+func (g *ConnectFour) full() bool {
+	for c := range c4Cols {
+		if g.board[c4Rows-1][c] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *ConnectFour) draw() {
+	var f launchpad.Frame
+	for r := range c4Rows {
+		for c := range c4Cols {
+			f.Set(c4Pos(r, c), c4Colors[g.board[r][c]])
+		}
+	}
+	for _, pos := range g.winLine {
+		f.SetPad(launchpad.Pad{Pos: pos, Color: c4Colors[g.player], LightMode: launchpad.Blinking})
+	}
+	if g.dropping {
+		f.Set(c4Pos(g.dropRow, g.dropCol), c4Colors[g.player])
+	}
+	if !g.finished {
+		for c := range c4Cols {
+			f.Set(c4Pos(c4TurnRow-1, c), c4Colors[g.player])
+		}
+	}
+	g.screen.Render(f)
+}
+
+// c4Pos converts zero based board coordinates to a pad.
+func c4Pos(row, col int) launchpad.PadPos {
+	return launchpad.PadPos{Row: uint8(row + 1), Col: uint8(col + 1)}
+}
@@ -0,0 +1,216 @@
+package games
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/LaunchPadStreamer/events"
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+func TestConnectFourLine(t *testing.T) {
+	// Boards are drawn top row first; '1' and '2' are the players' pieces.
+	tests := []struct {
+		name     string
+		board    []string
+		row, col int // the piece just dropped, zero based from the bottom
+		want     int // length of the line found, 0 for none
+	}{
+		{
+			name:  "horizontal from the end",
+			board: []string{".1111.."},
+			row:   0, col: 4,
+			want: 4,
+		},
+		{
+			name:  "horizontal through the middle",
+			board: []string{".1111.."},
+			row:   0, col: 2,
+			want: 4,
+		},
+		{
+			name:  "gap",
+			board: []string{"11.11.."},
+			row:   0, col: 1,
+			want: 0,
+		},
+		{
+			name:  "five in a row",
+			board: []string{"11111.."},
+			row:   0, col: 2,
+			want: 5,
+		},
+		{
+			name: "vertical",
+			board: []string{
+				"1......",
+				"1......",
+				"1......",
+				"1......",
+			},
+			row: 3, col: 0,
+			want: 4,
+		},
+		{
+			name: "three only",
+			board: []string{
+				"1......",
+				"1......",
+				"1......",
+				"2......",
+			},
+			row: 3, col: 0,
+			want: 0,
+		},
+		{
+			name: "diagonal through the middle",
+			board: []string{
+				"...1...",
+				"..12...",
+				".122...",
+				"1222...",
+			},
+			row: 1, col: 1,
+			want: 4,
+		},
+		{
+			name: "anti-diagonal",
+			board: []string{
+				"......2",
+				".....21",
+				"....211",
+				"...2111",
+			},
+			row: 3, col: 6,
+			want: 4,
+		},
+		{
+			name: "broken by the other player",
+			board: []string{
+				"...1...",
+				"..12...",
+				".212...",
+				"1222...",
+			},
+			row: 3, col: 3,
+			want: 0,
+		},
+		{
+			name: "top right corner",
+			board: []string{
+				"...1111",
+				"...2222",
+				"...1212",
+				"...2121",
+				"...1212",
+				"...2121",
+			},
+			row: 5, col: 6,
+			want: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &ConnectFour{}
+			for i, line := range tt.board {
+				row := len(tt.board) - 1 - i
+				for col, piece := range line {
+					if piece != '.' {
+						g.board[row][col] = int(piece - '0')
+					}
+				}
+			}
+			line := g.line(tt.row, tt.col)
+			if len(line) != tt.want {
+				t.Fatalf("found line %v, want %d pads", line, tt.want)
+			}
+			player := g.board[tt.row][tt.col]
+			for _, pos := range line {
+				if g.board[pos.Row-1][pos.Col-1] != player {
+					t.Errorf("line includes %v, not the player's piece", pos)
+				}
+			}
+			if line != nil && !slices.Contains(line, launchpad.PadPos{Row: uint8(tt.row + 1), Col: uint8(tt.col + 1)}) {
+				t.Error("line does not include the dropped piece")
+			}
+		})
+	}
+}
+
+func TestConnectFourGameOver(t *testing.T) {
+	tests := []struct {
+		name  string
+		board []string
+		col   int // the column the last piece of player 1 drops into
+		want  []events.GameOver
+	}{
+		{
+			name:  "win",
+			board: []string{"111...."},
+			col:   3,
+			want: []events.GameOver{
+				{Game: "Connect Four", Player: 1, Won: true},
+				{Game: "Connect Four", Player: 2},
+			},
+		},
+		{
+			name: "draw",
+			board: []string{
+				"221122.",
+				"1122112",
+				"2211221",
+				"1122112",
+				"2211221",
+				"1122112",
+			},
+			col: 6,
+			want: []events.GameOver{
+				{Game: "Connect Four", Player: 1},
+				{Game: "Connect Four", Player: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &ConnectFour{screen: &frameRecorder{}, player: 1}
+			for i, line := range tt.board {
+				row := len(tt.board) - 1 - i
+				for col, piece := range line {
+					if piece != '.' {
+						g.board[row][col] = int(piece - '0')
+					}
+				}
+			}
+			g.dropping, g.dropCol = true, tt.col
+			for g.dropTarget = 0; g.board[g.dropTarget][tt.col] != 0; g.dropTarget++ {
+			}
+			g.dropRow = g.dropTarget
+
+			published := make(chan events.GameOver, len(tt.want)+1)
+			unsubscribe := events.Subscribe(func(e events.GameOver) {
+				if e.Game == g.Name() {
+					published <- e
+				}
+			})
+			defer unsubscribe()
+			g.tick()
+
+			for _, want := range tt.want {
+				select {
+				case got := <-published:
+					if got != want {
+						t.Errorf("published %+v, want %+v", got, want)
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("%+v not published", want)
+				}
+			}
+			select {
+			case got := <-published:
+				t.Errorf("published %+v too", got)
+			case <-time.After(20 * time.Millisecond):
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package games
+
+import (
+	"fmt"
+	"math/bits"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/LaunchPadStreamer/events"
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+func init() {
+	RegisterGame(func() Game { return &Game2048{} })
+}
+
+const (
+	g2048Size = 4
+	// Each animation step moves sliding tiles one cell.
+	g2048StepInterval = 50 * time.Millisecond
+)
+
+// g2048Colors are the tile colours for 2, 4, 8 and so on. Tiles past the
+// end of the list use its last colour.
+var g2048Colors = []uint8{
+	launchpad.ColorWhite,
+	launchpad.ColorYellowLight,
+	launchpad.ColorOrange,
+	launchpad.ColorRed,
+	launchpad.ColorPink,
+	launchpad.ColorMagenta,
+	launchpad.ColorPurple,
+	launchpad.ColorBlue,
+	launchpad.ColorSky,
+	launchpad.ColorCyan,
+	launchpad.ColorGreen,
+	launchpad.ColorLime,
+}
+
+// g2048Slide is a tile moving during a move, in board coordinates.
+type g2048Slide struct {
+	fromRow, fromCol int
+	toRow, toCol     int
+	value            int
+}
+
+// Game2048 is the sliding tile game on the bottom left 4x4 quadrant. The
+// arrow buttons slide all tiles in their direction, merging equal tiles.
+// When no move is left the board blinks and the next arrow starts over.
+type Game2048 struct {
+	ticker *Ticker
+
+	mu     sync.Mutex
+	screen Screen
+	board  [g2048Size][g2048Size]int // [row][col], row 0 at the bottom
+	score  int
+	over   bool
+	// The move being animated: tiles slide for step ticks before next
+	// replaces the board.
+	slides []g2048Slide
+	dir    direction
+	step   int
+	next   [g2048Size][g2048Size]int
+}
+
+func (g *Game2048) Name() string { return "2048" }
+
+func (g *Game2048) Start(screen Screen) {
+	g.mu.Lock()
+	g.screen = screen
+	g.reset()
+	g.draw()
+	g.mu.Unlock()
+
+	g.ticker = StartTicker(g2048StepInterval, g.tick)
+}
+
+func (g *Game2048) Press(pos launchpad.PadPos, down bool) {
+	if !down {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var dir direction
+	switch pos {
+	case launchpad.ButtonUp:
+		dir = dirUp
+	case launchpad.ButtonDown:
+		dir = dirDown
+	case launchpad.ButtonLeft:
+		dir = dirLeft
+	case launchpad.ButtonRight:
+		dir = dirRight
+	default:
+		return
+	}
+	if g.over {
+		g.reset()
+		g.draw()
+		return
+	}
+	if g.slides != nil {
+		return
+	}
+
+	next, slides, gained := g2048Move(g.board, dir)
+	if next == g.board {
+		return
+	}
+	g.next, g.slides, g.dir, g.step = next, slides, dir, 0
+	g.score += gained
+	events.Publish(events.ScoreChanged{Game: g.Name(), Score: g.score})
+	g.draw()
+}
+
+func (g *Game2048) Controls() []Control {
+	return []Control{
+		{launchpad.ButtonUp, "Slide up"},
+		{launchpad.ButtonDown, "Slide down"},
+		{launchpad.ButtonLeft, "Slide left"},
+		{launchpad.ButtonRight, "Slide right"},
+	}
+}
+
+func (g *Game2048) Stop() {
+	g.ticker.Stop()
+}
+
+func (g *Game2048) reset() {
+	g.board = [g2048Size][g2048Size]int{}
+	g.score = 0
+	g.over = false
+	g.slides = nil
+	g.spawn()
+	g.spawn()
+}
+
+// tick advances the slide animation and finishes the move once every tile
+// has arrived.
+func (g *Game2048) tick() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.slides == nil {
+		return
+	}
+
+	g.step++
+	if g.step < g2048Size {
+		g.draw()
+		return
+	}
+	g.board = g.next
+	g.slides = nil
+	g.spawn()
+	if !g2048CanMove(g.board) {
+		g.over = true
+		fmt.Printf("2048: score %d\n", g.score)
+		events.Publish(events.GameOver{Game: g.Name(), Score: g.score})
+	}
+	g.draw()
+}
+
+// spawn puts a 2, or sometimes a 4, on a random empty cell.
+func (g *Game2048) spawn() {
+	var empty [][2]int
+	for r := range g2048Size {
+		for c := range g2048Size {
+			if g.board[r][c] == 0 {
+				empty = append(empty, [2]int{r, c})
+			}
+		}
+	}
+	if len(empty) == 0 {
+		return
+	}
+	cell := empty[rand.IntN(len(empty))]
+	g.board[cell[0]][cell[1]] = 2
+	if rand.IntN(10) == 0 {
+		g.board[cell[0]][cell[1]] = 4
+	}
+}
+
+func (g *Game2048) draw() {
+	var f launchpad.Frame
+	mode := launchpad.Permanent
+	if g.over {
+		mode = launchpad.Blinking
+	}
+	if g.slides != nil {
+		for _, s := range g.slides {
+			// Each tile moves one cell per step until it reaches its
+			// destination.
+			dist := max(abs(s.toRow-s.fromRow), abs(s.toCol-s.fromCol))
+			moved := min(g.step, dist)
+			f.Set(g2048Pos(s.fromRow+moved*g.dir.row, s.fromCol+moved*g.dir.col), g2048Color(s.value))
+		}
+	} else {
+		for r := range g2048Size {
+			for c := range g2048Size {
+				if v := g.board[r][c]; v != 0 {
+					f.SetPad(launchpad.Pad{Pos: g2048Pos(r, c), Color: g2048Color(v), LightMode: mode})
+				}
+			}
+		}
+	}
+	for _, button := range []launchpad.PadPos{launchpad.ButtonUp, launchpad.ButtonDown, launchpad.ButtonLeft, launchpad.ButtonRight} {
+		f.Set(button, launchpad.ColorWhite)
+	}
+	g.screen.Render(f)
+}
+
+// g2048Move slides every tile of board in dir. It returns the new board,
+// the path of every tile and the points scored by merges.
+func g2048Move(board [g2048Size][g2048Size]int, dir direction) ([g2048Size][g2048Size]int, []g2048Slide, int) {
+	var next [g2048Size][g2048Size]int
+	var slides []g2048Slide
+	score := 0
+
+	for line := range g2048Size {
+		// cells lists the line's cells starting at the edge tiles slide
+		// towards.
+		var cells [g2048Size][2]int
+		for i := range g2048Size {
+			switch dir {
+			case dirUp:
+				cells[i] = [2]int{g2048Size - 1 - i, line}
+			case dirDown:
+				cells[i] = [2]int{i, line}
+			case dirLeft:
+				cells[i] = [2]int{line, i}
+			case dirRight:
+				cells[i] = [2]int{line, g2048Size - 1 - i}
+			}
+		}
+
+		target, mergeable := -1, false
+		for _, from := range cells {
+			v := board[from[0]][from[1]]
+			if v == 0 {
+				continue
+			}
+			if target >= 0 && mergeable && next[cells[target][0]][cells[target][1]] == v {
+				next[cells[target][0]][cells[target][1]] = 2 * v
+				score += 2 * v
+				mergeable = false
+			} else {
+				target++
+				next[cells[target][0]][cells[target][1]] = v
+				mergeable = true
+			}
+			to := cells[target]
+			slides = append(slides, g2048Slide{from[0], from[1], to[0], to[1], v})
+		}
+	}
+	return next, slides, score
+}
+
+// g2048CanMove reports whether any move would change the board.
+func g2048CanMove(board [g2048Size][g2048Size]int) bool {
+	for _, dir := range []direction{dirUp, dirDown, dirLeft, dirRight} {
+		if next, _, _ := g2048Move(board, dir); next != board {
+			return true
+		}
+	}
+	return false
+}
+
+func g2048Color(value int) uint8 {
+	i := bits.Len(uint(value)) - 2
+	return g2048Colors[min(max(i, 0), len(g2048Colors)-1)]
+}
+
+// g2048Pos converts board coordinates to a pad of the bottom left quadrant.
+func g2048Pos(row, col int) launchpad.PadPos {
+	return launchpad.PadPos{Row: uint8(row + 1), Col: uint8(col + 1)}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,104 @@
+package games
+
+import "testing"
+
+type g2048Board = [g2048Size][g2048Size]int
+
+func TestG2048Move(t *testing.T) {
+	tests := []struct {
+		name  string
+		board g2048Board
+		dir   direction
+		want  g2048Board
+		score int
+	}{
+		{
+			name:  "four equal tiles merge in pairs",
+			board: g2048Board{{2, 2, 2, 2}},
+			dir:   dirLeft,
+			want:  g2048Board{{4, 4, 0, 0}},
+			score: 8,
+		},
+		{
+			name:  "merged tile does not merge again",
+			board: g2048Board{{4, 2, 2, 0}},
+			dir:   dirLeft,
+			want:  g2048Board{{4, 4, 0, 0}},
+			score: 4,
+		},
+		{
+			name:  "merge starts at the far edge",
+			board: g2048Board{{2, 2, 2, 0}},
+			dir:   dirRight,
+			want:  g2048Board{{0, 0, 2, 4}},
+			score: 4,
+		},
+		{
+			name:  "gaps close",
+			board: g2048Board{{0, 2, 0, 4}},
+			dir:   dirLeft,
+			want:  g2048Board{{2, 4, 0, 0}},
+		},
+		{
+			name:  "tiles separated by a gap merge",
+			board: g2048Board{{2, 0, 0, 2}},
+			dir:   dirLeft,
+			want:  g2048Board{{4, 0, 0, 0}},
+			score: 4,
+		},
+		{
+			name:  "up moves towards the last row",
+			board: g2048Board{{2}, {2}, {0}, {8}},
+			dir:   dirUp,
+			want:  g2048Board{{0}, {0}, {4}, {8}},
+			score: 4,
+		},
+		{
+			name:  "down moves towards row 0",
+			board: g2048Board{{0}, {4}, {4}, {4}},
+			dir:   dirDown,
+			want:  g2048Board{{8}, {4}, {0}, {0}},
+			score: 8,
+		},
+		{
+			name:  "blocked",
+			board: g2048Board{{2, 4, 0, 0}},
+			dir:   dirLeft,
+			want:  g2048Board{{2, 4, 0, 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, slides, score := g2048Move(tt.board, tt.dir)
+			if got != tt.want {
+				t.Errorf("board %v, want %v", got, tt.want)
+			}
+			if score != tt.score {
+				t.Errorf("score %d, want %d", score, tt.score)
+			}
+			for _, s := range slides {
+				if tt.board[s.fromRow][s.fromCol] != s.value {
+					t.Errorf("slide %+v does not start at its tile", s)
+				}
+			}
+		})
+	}
+}
+
+func TestG2048CanMove(t *testing.T) {
+	tests := []struct {
+		name  string
+		board g2048Board
+		want  bool
+	}{
+		{"empty cell", g2048Board{{2, 4, 2, 4}, {4, 2, 4, 2}, {2, 4, 2, 4}, {4, 2, 4, 0}}, true},
+		{"merge", g2048Board{{2, 4, 2, 4}, {4, 2, 4, 2}, {2, 4, 2, 4}, {4, 2, 4, 4}}, true},
+		{"vertical merge", g2048Board{{2, 4, 2, 4}, {4, 2, 4, 2}, {2, 4, 2, 4}, {2, 8, 16, 32}}, true},
+		{"stuck", g2048Board{{2, 4, 2, 4}, {4, 2, 4, 2}, {2, 4, 2, 4}, {4, 2, 4, 2}}, false},
+	}
+	for _, tt := range tests {
+		if got := g2048CanMove(tt.board); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Package games contains the games that run on the Launchpad grid and the
+// Manager that switches between them.
+//
+// Games register themselves with RegisterGame, usually from an init
+// function, so new games only need a file in this package or a plugin
+// loaded with LoadPlugins.
+package games
+
+import (
+	"sync"
+
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+// Screen is where a game draws its frames; launchpad.Device implements it.
+type Screen interface {
+	Render(f launchpad.Frame) error
+}
+
+// Game is a program that runs on the Launchpad while it is selected.
+type Game interface {
+	// Name identifies the game in logs.
+	Name() string
+	// Start is called when the game takes over the device. The game draws
+	// to screen until Stop is called.
+	Start(screen Screen)
+	// Press is called for every press and release of a pad or button
+	// routed to the game.
+	Press(pos launchpad.PadPos, down bool)
+	// Stop is called before another game takes over the device. Draws to
+	// the screen after Stop are discarded.
+	Stop()
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []func() Game
+)
+
+// RegisterGame adds a game to the set a Manager is created with. Games are
+// offered in the order they were registered.
+func RegisterGame(factory func() Game) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, factory)
+}
+
+// registered returns a fresh instance of every registered game.
+func registered() []Game {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	games := make([]Game, len(registry))
+	for i, factory := range registry {
+		games[i] = factory()
+	}
+	return games
+}
@@ -0,0 +1,78 @@
+package games
+
+import (
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+// Layout of the label sheet, in SVG user units.
+const (
+	labelCell   = 64
+	labelTitle  = 40
+	labelMargin = 20
+	labelPanel  = labelTitle + 9*labelCell + labelMargin
+)
+
+// WriteLabels writes an SVG sheet with one Launchpad diagram per
+// registered game, naming the buttons the game captures alongside the
+// Manager's own. It is meant to be printed as a guide for labelling the
+// device.
+func WriteLabels(w io.Writer) error {
+	games := registered()
+	width := 2*labelMargin + 9*labelCell
+	height := labelMargin + len(games)*labelPanel
+
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(w, `<rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+	for i, game := range games {
+		labels := map[launchpad.PadPos]string{
+			NextGameButton: "Next game",
+			PrevGameButton: "Previous game",
+		}
+		for _, control := range gameControls(game) {
+			if _, global := labels[control.Pos]; !global {
+				labels[control.Pos] = control.Label
+			}
+		}
+		writeLabelPanel(w, labelMargin, labelMargin+i*labelPanel, game.Name(), labels)
+	}
+	_, err := fmt.Fprintln(w, "</svg>")
+	return err
+}
+
+// gameControls returns the controls of game, or nil if it has none.
+func gameControls(game Game) []Control {
+	c, ok := game.(Controller)
+	if !ok {
+		return nil
+	}
+	return c.Controls()
+}
+
+func writeLabelPanel(w io.Writer, x, y int, title string, labels map[launchpad.PadPos]string) {
+	fmt.Fprintf(w, `<text x="%d" y="%d" font-size="20" font-weight="bold">%s</text>`+"\n",
+		x, y+24, html.EscapeString(title))
+	top := y + labelTitle
+	for row := uint8(1); row <= 9; row++ {
+		for col := uint8(1); col <= 9; col++ {
+			pos := launchpad.PadPos{Row: row, Col: col}
+			cx := x + int(col-1)*labelCell + labelCell/2
+			cy := top + int(9-row)*labelCell + labelCell/2
+			if pos.IsButton() {
+				fmt.Fprintf(w, `<circle cx="%d" cy="%d" r="%d" fill="none" stroke="black"/>`+"\n",
+					cx, cy, labelCell/2-6)
+			} else {
+				fmt.Fprintf(w, `<rect x="%d" y="%d" width="%d" height="%d" rx="6" fill="#eee" stroke="#999"/>`+"\n",
+					cx-labelCell/2+3, cy-labelCell/2+3, labelCell-6, labelCell-6)
+			}
+			if label, ok := labels[pos]; ok {
+				fmt.Fprintf(w, `<text x="%d" y="%d" font-size="10" text-anchor="middle">%s</text>`+"\n",
+					cx, cy+4, html.EscapeString(label))
+			}
+		}
+	}
+}
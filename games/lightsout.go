@@ -0,0 +1,149 @@
+package games
+
+import (
+	"math/rand/v2"
+	"sync"
+
+	"github.com/codeneuss/LaunchPadStreamer/events"
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+func init() {
+	RegisterGame(func() Game { return &LightsOut{} })
+}
+
+const (
+	lightsOutSize = 5
+	// lightsOutPresses is how many random presses scramble a new puzzle.
+	lightsOutPresses = 10
+)
+
+var (
+	lightsOutHint = launchpad.PadPos{Row: 9, Col: 8}
+	lightsOutNew  = launchpad.PadPos{Row: 9, Col: 7}
+)
+
+// LightsOut is the classic puzzle on a 5x5 board: pressing a pad toggles
+// it and its four neighbours, and the goal is to switch every light off.
+// Puzzles are scrambled from the solved board, so they can always be
+// solved, and the hint button pulses a pad that is part of a solution.
+type LightsOut struct {
+	mu     sync.Mutex
+	screen Screen
+	lights [lightsOutSize][lightsOutSize]bool
+	// solution marks the pads that switch every light off when each is
+	// pressed once. Pressing a pad toggles it, since two presses cancel.
+	solution [lightsOutSize][lightsOutSize]bool
+	hint     bool
+	solved   bool
+}
+
+func (g *LightsOut) Name() string { return "Lights Out" }
+
+func (g *LightsOut) Start(screen Screen) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.screen = screen
+	g.scramble()
+	g.draw()
+}
+
+func (g *LightsOut) Press(pos launchpad.PadPos, down bool) {
+	if !down {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch pos {
+	case lightsOutHint:
+		g.hint = !g.solved
+	case lightsOutNew:
+		g.scramble()
+	default:
+		row, col, ok := lightsOutCell(pos)
+		if !ok {
+			return
+		}
+		if g.solved {
+			g.scramble()
+			break
+		}
+		g.toggle(row, col)
+		g.hint = false
+		g.solved = g.lights == [lightsOutSize][lightsOutSize]bool{}
+		if g.solved {
+			events.Publish(events.GameOver{Game: g.Name(), Won: true})
+		}
+	}
+	g.draw()
+}
+
+func (g *LightsOut) Controls() []Control {
+	return []Control{
+		{lightsOutHint, "Hint"},
+		{lightsOutNew, "New puzzle"},
+	}
+}
+
+func (g *LightsOut) Stop() {}
+
+// scramble starts a new puzzle by pressing random pads of a dark board.
+func (g *LightsOut) scramble() {
+	g.lights = [lightsOutSize][lightsOutSize]bool{}
+	g.solution = [lightsOutSize][lightsOutSize]bool{}
+	for g.lights == [lightsOutSize][lightsOutSize]bool{} {
+		for range lightsOutPresses {
+			g.toggle(rand.IntN(lightsOutSize), rand.IntN(lightsOutSize))
+		}
+	}
+	g.hint = false
+	g.solved = false
+}
+
+// toggle presses the pad at row, col.
+func (g *LightsOut) toggle(row, col int) {
+	g.solution[row][col] = !g.solution[row][col]
+	for _, d := range [][2]int{{0, 0}, {1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		r, c := row+d[0], col+d[1]
+		if r >= 0 && r < lightsOutSize && c >= 0 && c < lightsOutSize {
+			g.lights[r][c] = !g.lights[r][c]
+		}
+	}
+}
+
+func (g *LightsOut) draw() {
+	var f launchpad.Frame
+	hinted := false
+	for r := range lightsOutSize {
+		for c := range lightsOutSize {
+			pad := launchpad.Pad{Pos: lightsOutPos(r, c), Color: launchpad.ColorBlueDim}
+			switch {
+			case g.solved:
+				pad.Color, pad.LightMode = launchpad.ColorGreen, launchpad.Blinking
+			case g.lights[r][c]:
+				pad.Color = launchpad.ColorYellow
+			}
+			if g.hint && !hinted && g.solution[r][c] {
+				pad.LightMode = launchpad.Pulsing
+				hinted = true
+			}
+			f.SetPad(pad)
+		}
+	}
+	f.Set(lightsOutHint, launchpad.ColorCyan)
+	f.Set(lightsOutNew, launchpad.ColorWhite)
+	g.screen.Render(f)
+}
+
+// lightsOutCell converts a pad to board coordinates; the board sits in
+// rows and columns 2 to 6.
+func lightsOutCell(pos launchpad.PadPos) (row, col int, ok bool) {
+	row, col = int(pos.Row)-2, int(pos.Col)-2
+	ok = row >= 0 && row < lightsOutSize && col >= 0 && col < lightsOutSize
+	return row, col, ok
+}
+
+func lightsOutPos(row, col int) launchpad.PadPos {
+	return launchpad.PadPos{Row: uint8(row + 2), Col: uint8(col + 2)}
+}
@@ -0,0 +1,179 @@
+package games
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/codeneuss/LaunchPadStreamer/events"
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+// Buttons the Manager uses to switch games. Games cannot capture them.
+var (
+	NextGameButton = launchpad.PadPos{Row: 8, Col: 9}
+	PrevGameButton = launchpad.PadPos{Row: 7, Col: 9}
+)
+
+// Control is a button a game handles itself, with a short description of
+// what it does.
+type Control struct {
+	Pos   launchpad.PadPos
+	Label string
+}
+
+// Controller is implemented by games that use control buttons. Presses of
+// the buttons a game lists are passed to it; all other buttons stay with
+// the Manager. Grid pads always go to the game.
+//
+// Controls may be called before Start, and on games that are never
+// started, so it must not depend on the game running.
+type Controller interface {
+	Controls() []Control
+}
+
+// Manager runs one game at a time on a screen and routes input to it.
+type Manager struct {
+	out   Screen
+	games []Game
+
+	mu      sync.Mutex
+	current int
+	screen  *gameScreen
+}
+
+// NewManager creates an instance of every registered game, drawing to out.
+func NewManager(out Screen) *Manager {
+	return &Manager{
+		out:     out,
+		games:   registered(),
+		current: -1,
+	}
+}
+
+// Start runs the first registered game.
+func (m *Manager) Start() {
+	m.Switch(0)
+}
+
+// Stop stops the running game.
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopCurrent()
+}
+
+// Switch stops the running game and starts the game at index i, wrapping
+// around at either end of the list.
+func (m *Manager) Switch(i int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.games) == 0 {
+		return
+	}
+	m.stopCurrent()
+
+	m.current = (i%len(m.games) + len(m.games)) % len(m.games)
+	game := m.games[m.current]
+
+	// The buttons are captured before the game starts, so its first frame
+	// already shows them.
+	captured := make(map[launchpad.PadPos]bool)
+	if c, ok := game.(Controller); ok {
+		for _, control := range c.Controls() {
+			if control.Pos != NextGameButton && control.Pos != PrevGameButton {
+				captured[control.Pos] = true
+			}
+		}
+	}
+	m.screen = &gameScreen{m: m, captured: captured}
+	m.out.Render(m.decorate(launchpad.Frame{}, captured))
+
+	fmt.Printf("Game: %s\n", game.Name())
+	game.Start(m.screen)
+	events.Publish(events.GameStarted{Game: game.Name()})
+}
+
+// Press routes a pad press to the running game, or handles it if it is a
+// button the game has not captured.
+func (m *Manager) Press(pos launchpad.PadPos, down bool) {
+	m.mu.Lock()
+	var game Game
+	if m.current >= 0 && (!pos.IsButton() || m.screen.captures(pos)) {
+		game = m.games[m.current]
+	}
+	m.mu.Unlock()
+	if game != nil {
+		game.Press(pos, down)
+		return
+	}
+
+	if !down {
+		return
+	}
+	switch pos {
+	case NextGameButton:
+		m.Switch(m.index() + 1)
+	case PrevGameButton:
+		m.Switch(m.index() - 1)
+	}
+}
+
+func (m *Manager) index() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.current
+}
+
+func (m *Manager) stopCurrent() {
+	if m.current < 0 {
+		return
+	}
+	m.screen.close()
+	m.games[m.current].Stop()
+	m.current = -1
+}
+
+// decorate lights the Manager's buttons on top of a game frame. Right
+// column buttons the game has not captured are kept dark.
+func (m *Manager) decorate(f launchpad.Frame, captured map[launchpad.PadPos]bool) launchpad.Frame {
+	for r := uint8(1); r <= 8; r++ {
+		if pos := (launchpad.PadPos{Row: r, Col: 9}); !captured[pos] {
+			f.Set(pos, launchpad.ColorOff)
+		}
+	}
+	if len(m.games) > 1 {
+		f.Set(NextGameButton, launchpad.ColorWhite)
+		f.Set(PrevGameButton, launchpad.ColorWhite)
+	}
+	return f
+}
+
+// gameScreen is the Screen handed to a running game. Once the game is
+// stopped its draws are dropped, so a late tick cannot paint over the next
+// game.
+type gameScreen struct {
+	m        *Manager
+	captured map[launchpad.PadPos]bool
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (s *gameScreen) Render(f launchpad.Frame) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	return s.m.out.Render(s.m.decorate(f, s.captured))
+}
+
+func (s *gameScreen) captures(pos launchpad.PadPos) bool {
+	return s.captured[pos]
+}
+
+func (s *gameScreen) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
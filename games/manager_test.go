@@ -0,0 +1,42 @@
+package games
+
+import (
+	"testing"
+
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+// buttonGame lights the right column buttons it captures when it starts.
+type buttonGame struct{}
+
+var buttonGameControls = []Control{{launchpad.PadPos{Row: 1, Col: 9}, "Down"}, {launchpad.PadPos{Row: 2, Col: 9}, "Up"}}
+
+func (buttonGame) Name() string { return "Buttons" }
+
+func (buttonGame) Start(screen Screen) {
+	var f launchpad.Frame
+	for _, c := range buttonGameControls {
+		f.Set(c.Pos, launchpad.ColorRed)
+	}
+	screen.Render(f)
+}
+
+func (buttonGame) Press(launchpad.PadPos, bool) {}
+func (buttonGame) Stop()                        {}
+func (buttonGame) Controls() []Control          { return buttonGameControls }
+
+func TestManagerFirstFrameShowsCapturedButtons(t *testing.T) {
+	screen := &frameRecorder{}
+	m := &Manager{out: screen, games: []Game{buttonGame{}}, current: -1}
+	m.Start()
+	defer m.Stop()
+
+	for _, c := range buttonGameControls {
+		if got := screen.frame.Pad(c.Pos).Color; got != launchpad.ColorRed {
+			t.Errorf("captured button %v is %d on the first frame, want red", c.Pos, got)
+		}
+	}
+	if got := screen.frame.Pad(launchpad.PadPos{Row: 3, Col: 9}).Color; got != launchpad.ColorOff {
+		t.Errorf("uncaptured button is %d, want off", got)
+	}
+}
@@ -0,0 +1,164 @@
+package games
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func init() {
+	RegisterGame(func() Game { return &Piano{} })
+}
+
+const (
+	pianoChannel  = 0
+	pianoVelocity = 100
+	// pianoBase is the note of the bottom left pad in the key of C, C3.
+	pianoBase      = 48
+	pianoMaxOctave = 3
+	pianoMinOctave = -3
+)
+
+var pianoScaleButton = launchpad.PadPos{Row: 9, Col: 5}
+
+// pianoScales are the intervals of each scale in semitones from the root.
+var pianoScales = []struct {
+	name      string
+	intervals []int
+}{
+	{"Major", []int{0, 2, 4, 5, 7, 9, 11}},
+	{"Minor", []int{0, 2, 3, 5, 7, 8, 10}},
+	{"Dorian", []int{0, 2, 3, 5, 7, 9, 10}},
+	{"Pentatonic", []int{0, 2, 4, 7, 9}},
+	{"Chromatic", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}},
+}
+
+var pianoKeys = []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+
+// Piano turns the grid into an instrument laid out in fourths, like
+// Ableton Push: in a scale each row starts three scale steps above the
+// one below, chromatically five semitones. Root notes are lit and played
+// notes go to the note output.
+type Piano struct {
+	mu     sync.Mutex
+	screen Screen
+	scale  int
+	key    int
+	octave int
+	held   map[launchpad.PadPos]uint8
+}
+
+func (g *Piano) Name() string { return "Piano" }
+
+func (g *Piano) Start(screen Screen) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.screen = screen
+	g.held = make(map[launchpad.PadPos]uint8)
+	g.draw()
+}
+
+func (g *Piano) Press(pos launchpad.PadPos, down bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if onGrid(pos) {
+		if down {
+			note := g.note(pos)
+			g.held[pos] = note
+			sendNote(midi.NoteOn(pianoChannel, note, pianoVelocity))
+		} else if note, ok := g.held[pos]; ok {
+			delete(g.held, pos)
+			sendNote(midi.NoteOff(pianoChannel, note))
+		}
+		g.draw()
+		return
+	}
+	if !down {
+		return
+	}
+	switch pos {
+	case launchpad.ButtonUp:
+		g.octave = min(g.octave+1, pianoMaxOctave)
+	case launchpad.ButtonDown:
+		g.octave = max(g.octave-1, pianoMinOctave)
+	case launchpad.ButtonLeft:
+		g.key = (g.key + len(pianoKeys) - 1) % len(pianoKeys)
+	case launchpad.ButtonRight:
+		g.key = (g.key + 1) % len(pianoKeys)
+	case pianoScaleButton:
+		g.scale = (g.scale + 1) % len(pianoScales)
+	default:
+		return
+	}
+	fmt.Printf("Scale: %s %s\n", pianoKeys[g.key], pianoScales[g.scale].name)
+	g.draw()
+}
+
+func (g *Piano) Controls() []Control {
+	return []Control{
+		{launchpad.ButtonUp, "Octave up"},
+		{launchpad.ButtonDown, "Octave down"},
+		{launchpad.ButtonLeft, "Key down"},
+		{launchpad.ButtonRight, "Key up"},
+		{pianoScaleButton, "Scale"},
+	}
+}
+
+func (g *Piano) Stop() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for pos, note := range g.held {
+		sendNote(midi.NoteOff(pianoChannel, note))
+		delete(g.held, pos)
+	}
+}
+
+// degree returns the position of a pad in the layout: scale steps in a
+// scale, semitones in the chromatic one.
+func (g *Piano) degree(pos launchpad.PadPos) int {
+	row, col := int(pos.Row)-1, int(pos.Col)-1
+	if len(pianoScales[g.scale].intervals) == 12 {
+		return row*5 + col
+	}
+	return row*3 + col
+}
+
+// note returns the MIDI note of a grid pad, clamped to the MIDI range.
+func (g *Piano) note(pos launchpad.PadPos) uint8 {
+	intervals := pianoScales[g.scale].intervals
+	d := g.degree(pos)
+	n := pianoBase + g.key + g.octave*12 + d/len(intervals)*12 + intervals[d%len(intervals)]
+	return uint8(min(max(n, 0), 127))
+}
+
+func (g *Piano) draw() {
+	var f launchpad.Frame
+	playing := make(map[uint8]bool)
+	for _, note := range g.held {
+		playing[note] = true
+	}
+	chromatic := len(pianoScales[g.scale].intervals) == 12
+	for row := range uint8(8) {
+		for col := range uint8(8) {
+			pos := launchpad.PadPos{Row: row + 1, Col: col + 1}
+			note := g.note(pos)
+			switch {
+			case playing[note]:
+				f.Set(pos, launchpad.ColorGreen)
+			case int(note)%12 == g.key%12:
+				f.Set(pos, launchpad.ColorBlue)
+			case !chromatic:
+				f.Set(pos, launchpad.ColorWhite)
+			}
+		}
+	}
+	f.Set(launchpad.ButtonUp, launchpad.ColorWhite)
+	f.Set(launchpad.ButtonDown, launchpad.ColorWhite)
+	f.Set(launchpad.ButtonLeft, launchpad.ColorBlue)
+	f.Set(launchpad.ButtonRight, launchpad.ColorBlue)
+	f.Set(pianoScaleButton, launchpad.ColorPurple)
+	g.screen.Render(f)
+}
@@ -0,0 +1,23 @@
+package games
+
+import (
+	"fmt"
+	"path/filepath"
+	"plugin"
+)
+
+// LoadPlugins opens every Go plugin (*.so) in dir. Plugins register their
+// games with RegisterGame from an init function, which runs when the
+// plugin is opened. A missing directory is not an error.
+func LoadPlugins(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if _, err := plugin.Open(path); err != nil {
+			return fmt.Errorf("loading plugin %s: %w", path, err)
+		}
+	}
+	return nil
+}
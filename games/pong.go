@@ -0,0 +1,188 @@
+package games
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/LaunchPadStreamer/events"
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+	"github.com/codeneuss/LaunchPadStreamer/physics"
+)
+
+func init() {
+	RegisterGame(func() Game { return &Pong{} })
+}
+
+const (
+	pongInterval  = 50 * time.Millisecond
+	pongPaddle    = 2 // paddle height in pads
+	pongWinScore  = 4
+	pongOverTicks = 30
+
+	// Ball speed in pads per second. Every return speeds it up until the
+	// cap.
+	pongSpeed    = 4.0
+	pongSpeedUp  = 1.1
+	pongMaxSpeed = 12.0
+)
+
+var (
+	pongColors = [2]uint8{launchpad.ColorRed, launchpad.ColorBlue}
+
+	// Buttons of the right player in the right column.
+	pongRightUp   = launchpad.PadPos{Row: 2, Col: 9}
+	pongRightDown = launchpad.PadPos{Row: 1, Col: 9}
+)
+
+// Pong is two player pong with paddles on the left and right edges of the
+// grid. The left player moves with the up and down arrows, the right player
+// with the two bottom buttons of the right column. The ball speeds up with
+// every return; the scores fill the top row from either end.
+type Pong struct {
+	ticker *Ticker
+
+	mu      sync.Mutex
+	screen  Screen
+	world   physics.World
+	ball    physics.Body
+	paddles [2]int // bottom row of each paddle
+	scores  [2]int
+	over    int
+	winner  int
+}
+
+func (g *Pong) Name() string { return "Pong" }
+
+func (g *Pong) Start(screen Screen) {
+	g.mu.Lock()
+	g.screen = screen
+	// The ball moves between the paddles; reaching a side means it is
+	// either returned or missed.
+	g.world = physics.World{Bounds: physics.Rect{MinX: 2, MinY: 1, MaxX: 7, MaxY: 8}}
+	g.scores = [2]int{}
+	g.over = 0
+	g.paddles = [2]int{4, 4}
+	g.serve(rand.IntN(2))
+	g.draw()
+	g.mu.Unlock()
+
+	g.ticker = StartTicker(pongInterval, g.tick)
+}
+
+func (g *Pong) Press(pos launchpad.PadPos, down bool) {
+	if !down {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch pos {
+	case launchpad.ButtonUp:
+		g.movePaddle(0, 1)
+	case launchpad.ButtonDown:
+		g.movePaddle(0, -1)
+	case pongRightUp:
+		g.movePaddle(1, 1)
+	case pongRightDown:
+		g.movePaddle(1, -1)
+	default:
+		return
+	}
+	g.draw()
+}
+
+func (g *Pong) Controls() []Control {
+	return []Control{
+		{launchpad.ButtonUp, "Left up"},
+		{launchpad.ButtonDown, "Left down"},
+		{pongRightUp, "Right up"},
+		{pongRightDown, "Right down"},
+	}
+}
+
+func (g *Pong) Stop() {
+	g.ticker.Stop()
+}
+
+func (g *Pong) movePaddle(player, delta int) {
+	g.paddles[player] = min(max(g.paddles[player]+delta, 1), 8-pongPaddle+1)
+}
+
+// serve puts the ball in the middle, heading for player.
+func (g *Pong) serve(player int) {
+	vx := pongSpeed
+	if player == 0 {
+		vx = -vx
+	}
+	g.ball = physics.Body{X: 4.5, Y: float64(rand.IntN(4) + 3), VX: vx, VY: rand.Float64()*4 - 2}
+}
+
+func (g *Pong) tick() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.over > 0 {
+		g.over--
+		if g.over == 0 {
+			g.scores = [2]int{}
+			g.serve(1 - g.winner)
+		}
+		g.draw()
+		return
+	}
+
+	hit := g.world.Step(&g.ball, pongInterval.Seconds())
+	for player, side := range []physics.Side{physics.Left, physics.Right} {
+		if hit.Walls&side == 0 {
+			continue
+		}
+		row := int(g.ball.Pos().Row)
+		if row >= g.paddles[player] && row < g.paddles[player]+pongPaddle {
+			speed := min(math.Abs(g.ball.VX)*pongSpeedUp, pongMaxSpeed)
+			g.ball.VX = math.Copysign(speed, g.ball.VX)
+			continue
+		}
+		other := 1 - player
+		g.scores[other]++
+		events.Publish(events.ScoreChanged{Game: g.Name(), Player: other + 1, Score: g.scores[other]})
+		if g.scores[other] >= pongWinScore {
+			fmt.Printf("Pong: %d:%d\n", g.scores[0], g.scores[1])
+			g.winner = other
+			events.Publish(events.GameOver{Game: g.Name(), Player: other + 1, Score: g.scores[other], Won: true})
+			events.Publish(events.GameOver{Game: g.Name(), Player: player + 1, Score: g.scores[player]})
+			g.over = pongOverTicks
+		} else {
+			g.serve(player)
+		}
+	}
+	g.draw()
+}
+
+func (g *Pong) draw() {
+	var f launchpad.Frame
+	if g.over > 0 {
+		f.Fill(pongColors[g.winner])
+		g.screen.Render(f)
+		return
+	}
+
+	for player, col := range []uint8{1, 8} {
+		for i := range pongPaddle {
+			f.Set(launchpad.PadPos{Row: uint8(g.paddles[player] + i), Col: col}, pongColors[player])
+		}
+	}
+	f.Set(g.ball.Pos(), launchpad.ColorWhite)
+
+	for i := range g.scores[0] {
+		f.Set(launchpad.PadPos{Row: 9, Col: uint8(1 + i)}, pongColors[0])
+	}
+	for i := range g.scores[1] {
+		f.Set(launchpad.PadPos{Row: 9, Col: uint8(8 - i)}, pongColors[1])
+	}
+	f.Set(pongRightUp, pongColors[1])
+	f.Set(pongRightDown, pongColors[1])
+	g.screen.Render(f)
+}
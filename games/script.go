@@ -0,0 +1,376 @@
+package games
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// LoadScripts registers every Lua script (*.lua) in dir as a game named
+// after its file. A missing directory is not an error.
+//
+// A script may define the global functions start(), press(row, col, down)
+// and stop(). It draws with the pad table:
+//
+//	pad.set(row, col, color [, mode])    light a pad, mode is pad.STATIC,
+//	                                     pad.FLASH or pad.PULSE
+//	pad.flash(row, col, colorA, colorB)  alternate a pad between colours
+//	pad.get(row, col)                    colour of a pad
+//	pad.fill(color)                      set every grid pad
+//	pad.clear()                          switch every pad off
+//	pad.every(ms, fn)                    call fn repeatedly, returns a timer id
+//	pad.after(ms, fn)                    call fn once, returns a timer id
+//	pad.cancel(id)                       stop a timer
+//
+// Colour names are available in the colors table (colors.red, ...). The
+// frame is sent to the device after every callback. Control buttons are
+// only passed to press if the script lists them in a global controls
+// table of {row, col, label} entries. The table is read once here, by
+// running the top level of the script without calling start, so changes
+// to it take effect on the next run of the program.
+//
+// Rows and columns go from 1 to 9 and colours are palette indices from 0
+// to 127. Loading the script and each callback may run for at most a
+// second; a script that errors or runs over is stopped.
+func LoadScripts(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.lua"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		controls := readControls(path)
+		RegisterGame(func() Game { return &Script{path: path, controls: controls} })
+	}
+	return nil
+}
+
+// discardScreen drops every frame.
+type discardScreen struct{}
+
+func (discardScreen) Render(launchpad.Frame) error { return nil }
+
+// scriptTimeout bounds how long loading a script or one callback may run,
+// so a script stuck in a loop cannot freeze the game switcher and input.
+const scriptTimeout = time.Second
+
+// scriptColors are the colour names exposed to scripts.
+var scriptColors = map[string]uint8{
+	"off":         launchpad.ColorOff,
+	"white":       launchpad.ColorWhite,
+	"red":         launchpad.ColorRed,
+	"redDim":      launchpad.ColorRedDim,
+	"redLight":    launchpad.ColorRedLight,
+	"orange":      launchpad.ColorOrange,
+	"orangeDim":   launchpad.ColorOrangeDim,
+	"yellow":      launchpad.ColorYellow,
+	"yellowLight": launchpad.ColorYellowLight,
+	"lime":        launchpad.ColorLime,
+	"green":       launchpad.ColorGreen,
+	"greenDim":    launchpad.ColorGreenDim,
+	"greenLight":  launchpad.ColorGreenLight,
+	"mint":        launchpad.ColorMint,
+	"cyan":        launchpad.ColorCyan,
+	"cyanLight":   launchpad.ColorCyanLight,
+	"sky":         launchpad.ColorSky,
+	"blue":        launchpad.ColorBlue,
+	"blueDim":     launchpad.ColorBlueDim,
+	"blueLight":   launchpad.ColorBlueLight,
+	"purple":      launchpad.ColorPurple,
+	"purpleLight": launchpad.ColorPurpleLight,
+	"magenta":     launchpad.ColorMagenta,
+	"pink":        launchpad.ColorPink,
+	"pinkLight":   launchpad.ColorPinkLight,
+	"hotPink":     launchpad.ColorHotPink,
+}
+
+// Script is a game implemented by a Lua script. The script is loaded
+// afresh every time the game starts, so edits other than to its controls
+// take effect on the next switch to it.
+type Script struct {
+	path     string
+	controls []Control
+
+	mu        sync.Mutex
+	state     *lua.LState
+	screen    Screen
+	frame     launchpad.Frame
+	timers    map[int]chan struct{}
+	nextTimer int
+}
+
+func (g *Script) Name() string {
+	return strings.TrimSuffix(filepath.Base(g.path), filepath.Ext(g.path))
+}
+
+func (g *Script) Start(screen Screen) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.load(screen) {
+		g.call("start")
+	}
+}
+
+// load runs the top level of the script in a fresh state and reports
+// whether it succeeded. The caller holds g.mu.
+func (g *Script) load(screen Screen) bool {
+	g.screen = screen
+	g.frame = launchpad.Frame{}
+	g.timers = make(map[int]chan struct{})
+
+	g.state = lua.NewState()
+	g.state.SetGlobal("pad", g.padTable())
+	colors := g.state.NewTable()
+	for name, color := range scriptColors {
+		colors.RawSetString(name, lua.LNumber(color))
+	}
+	g.state.SetGlobal("colors", colors)
+
+	if err := g.run(func() error { return g.state.DoFile(g.path) }); err != nil {
+		g.fail(err)
+		return false
+	}
+	return true
+}
+
+func (g *Script) Press(pos launchpad.PadPos, down bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.call("press", lua.LNumber(pos.Row), lua.LNumber(pos.Col), lua.LBool(down))
+}
+
+// Controls returns the controls table read when the script was loaded.
+func (g *Script) Controls() []Control {
+	return g.controls
+}
+
+// readControls reads the controls table of the script at path by loading
+// it on its own: start is not called and timers set up by the top level
+// never fire. Entries outside the grid are reported and left out.
+func readControls(path string) []Control {
+	probe := &Script{path: path}
+	probe.mu.Lock()
+	defer probe.mu.Unlock()
+	if !probe.load(discardScreen{}) {
+		return nil
+	}
+	defer probe.close()
+
+	table, ok := probe.state.GetGlobal("controls").(*lua.LTable)
+	if !ok {
+		return nil
+	}
+	var controls []Control
+	table.ForEach(func(k, v lua.LValue) {
+		entry, ok := v.(*lua.LTable)
+		if !ok {
+			return
+		}
+		row, _ := entry.RawGetInt(1).(lua.LNumber)
+		col, _ := entry.RawGetInt(2).(lua.LNumber)
+		if row < 1 || row > 9 || col < 1 || col > 9 {
+			fmt.Printf("Script Error: %s: controls entry %s: row and column must be between 1 and 9\n", probe.Name(), k)
+			return
+		}
+		controls = append(controls, Control{
+			Pos:   launchpad.PadPos{Row: uint8(row), Col: uint8(col)},
+			Label: lua.LVAsString(entry.RawGetInt(3)),
+		})
+	})
+	return controls
+}
+
+func (g *Script) Stop() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.state == nil {
+		return
+	}
+	g.call("stop")
+	if g.state != nil {
+		g.close()
+	}
+}
+
+// call runs a global script function if it is defined and sends the frame.
+// The caller holds g.mu.
+func (g *Script) call(name string, args ...lua.LValue) {
+	if g.state == nil {
+		return
+	}
+	fn, ok := g.state.GetGlobal(name).(*lua.LFunction)
+	if !ok {
+		return
+	}
+	g.callFunction(fn, args...)
+}
+
+func (g *Script) callFunction(fn *lua.LFunction, args ...lua.LValue) {
+	err := g.run(func() error {
+		return g.state.CallByParam(lua.P{Fn: fn, NRet: 0, Protect: true}, args...)
+	})
+	if err != nil {
+		g.fail(err)
+		return
+	}
+	g.screen.Render(g.frame)
+}
+
+// run runs script code, aborting it after scriptTimeout.
+func (g *Script) run(fn func() error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), scriptTimeout)
+	defer cancel()
+	g.state.SetContext(ctx)
+	defer g.state.RemoveContext()
+	return fn()
+}
+
+// fail reports a script error and shuts the script down, leaving the last
+// frame on the device.
+func (g *Script) fail(err error) {
+	fmt.Printf("Script Error: %s: %v\n", g.Name(), err)
+	g.close()
+}
+
+// close cancels the timers and closes the state. The caller holds g.mu.
+func (g *Script) close() {
+	for id := range g.timers {
+		g.cancelTimer(id)
+	}
+	g.state.Close()
+	g.state = nil
+}
+
+func (g *Script) padTable() *lua.LTable {
+	L := g.state
+	t := L.NewTable()
+	t.RawSetString("STATIC", lua.LNumber(launchpad.Permanent))
+	t.RawSetString("FLASH", lua.LNumber(launchpad.Blinking))
+	t.RawSetString("PULSE", lua.LNumber(launchpad.Pulsing))
+
+	L.SetFuncs(t, map[string]lua.LGFunction{
+		"set": func(L *lua.LState) int {
+			g.frame.SetPad(launchpad.Pad{
+				Pos:       checkPos(L),
+				Color:     checkColor(L, 3),
+				LightMode: checkMode(L, 4),
+			})
+			return 0
+		},
+		"flash": func(L *lua.LState) int {
+			g.frame.SetFlash(checkPos(L), checkColor(L, 3), checkColor(L, 4))
+			return 0
+		},
+		"get": func(L *lua.LState) int {
+			L.Push(lua.LNumber(g.frame.Pad(checkPos(L)).Color))
+			return 1
+		},
+		"fill": func(L *lua.LState) int {
+			g.frame.Fill(checkColor(L, 1))
+			return 0
+		},
+		"clear": func(L *lua.LState) int {
+			g.frame = launchpad.Frame{}
+			return 0
+		},
+		"every": func(L *lua.LState) int {
+			L.Push(lua.LNumber(g.startTimer(L, true)))
+			return 1
+		},
+		"after": func(L *lua.LState) int {
+			L.Push(lua.LNumber(g.startTimer(L, false)))
+			return 1
+		},
+		"cancel": func(L *lua.LState) int {
+			g.cancelTimer(L.CheckInt(1))
+			return 0
+		},
+	})
+	return t
+}
+
+// checkPos returns arguments 1 and 2 as a pad position. Rows and columns
+// outside 1 to 9 would wrap around to other pads.
+func checkPos(L *lua.LState) launchpad.PadPos {
+	row, col := L.CheckInt(1), L.CheckInt(2)
+	if row < 1 || row > 9 {
+		L.ArgError(1, "row must be between 1 and 9")
+	}
+	if col < 1 || col > 9 {
+		L.ArgError(2, "column must be between 1 and 9")
+	}
+	return launchpad.PadPos{Row: uint8(row), Col: uint8(col)}
+}
+
+// checkColor returns argument n as a palette index. Anything above 127
+// would not fit a MIDI data byte and break the message it is sent in.
+func checkColor(L *lua.LState, n int) uint8 {
+	color := L.CheckInt(n)
+	if color < 0 || color > 127 {
+		L.ArgError(n, "colour must be between 0 and 127")
+	}
+	return uint8(color)
+}
+
+// checkMode returns the optional argument n as a light mode.
+func checkMode(L *lua.LState, n int) launchpad.LightMode {
+	mode := L.OptInt(n, int(launchpad.Permanent))
+	if mode < int(launchpad.Permanent) || mode > int(launchpad.Pulsing) {
+		L.ArgError(n, "mode must be pad.STATIC, pad.FLASH or pad.PULSE")
+	}
+	return launchpad.LightMode(mode)
+}
+
+// startTimer runs the function argument after the given delay, repeatedly
+// if repeat is set. Timers run under g.mu like every other callback and
+// end when the script stops. The caller holds g.mu.
+func (g *Script) startTimer(L *lua.LState, repeat bool) int {
+	interval := time.Duration(L.CheckInt(1)) * time.Millisecond
+	fn := L.CheckFunction(2)
+	if interval <= 0 {
+		L.ArgError(1, "interval must be positive")
+	}
+
+	g.nextTimer++
+	id := g.nextTimer
+	done := make(chan struct{})
+	g.timers[id] = done
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			g.mu.Lock()
+			select {
+			case <-done:
+				g.mu.Unlock()
+				return
+			default:
+			}
+			if !repeat {
+				g.cancelTimer(id)
+			}
+			g.callFunction(fn)
+			g.mu.Unlock()
+		}
+	}()
+	return id
+}
+
+// cancelTimer stops a timer. The caller holds g.mu.
+func (g *Script) cancelTimer(id int) {
+	if done, ok := g.timers[id]; ok {
+		close(done)
+		delete(g.timers, id)
+	}
+}
@@ -0,0 +1,101 @@
+package games
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+// frameRecorder is a Screen keeping the last frame rendered.
+type frameRecorder struct {
+	frame launchpad.Frame
+}
+
+func (r *frameRecorder) Render(f launchpad.Frame) error {
+	r.frame = f
+	return nil
+}
+
+func startScript(t *testing.T, source string) (*Script, *frameRecorder) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.lua")
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := &Script{path: path}
+	screen := &frameRecorder{}
+	g.Start(screen)
+	t.Cleanup(g.Stop)
+	return g, screen
+}
+
+func TestScriptDraws(t *testing.T) {
+	_, screen := startScript(t, `
+function start()
+	pad.set(1, 1, colors.red)
+	pad.set(1, 2, colors.blue, pad.PULSE)
+	pad.flash(1, 3, colors.red, colors.white)
+end`)
+
+	want := []launchpad.Pad{
+		{Pos: launchpad.PadPos{Row: 1, Col: 1}, Color: launchpad.ColorRed},
+		{Pos: launchpad.PadPos{Row: 1, Col: 2}, Color: launchpad.ColorBlue, LightMode: launchpad.Pulsing},
+		{Pos: launchpad.PadPos{Row: 1, Col: 3}, Color: launchpad.ColorRed, FlashColor: launchpad.ColorWhite, LightMode: launchpad.Blinking},
+	}
+	for _, pad := range want {
+		if got := screen.frame.Pad(pad.Pos); got != pad {
+			t.Errorf("pad %v is %+v, want %+v", pad.Pos, got, pad)
+		}
+	}
+}
+
+func TestScriptRejectsBadArguments(t *testing.T) {
+	tests := []struct {
+		name, call string
+	}{
+		{"colour above 127", "pad.set(1, 1, 200)"},
+		{"negative colour", "pad.set(1, 1, -1)"},
+		{"unknown mode", "pad.set(1, 1, 5, 3)"},
+		{"flash colour", "pad.flash(1, 1, 5, 128)"},
+		{"fill colour", "pad.fill(255)"},
+		{"row 0", "pad.set(0, 1, 5)"},
+		{"negative column", "pad.flash(1, -1, 5, 3)"},
+		{"row above 9", "pad.get(10, 1)"},
+		{"column wrapping to 1", "pad.set(1, 257, 5)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, screen := startScript(t, "function start() "+tt.call+" end")
+			if g.state != nil {
+				t.Error("script still running after a bad argument")
+			}
+			if screen.frame != (launchpad.Frame{}) {
+				t.Error("bad argument was drawn")
+			}
+		})
+	}
+}
+
+func TestScriptTimeout(t *testing.T) {
+	g, _ := startScript(t, "while true do end")
+	if g.state != nil {
+		t.Error("script still running after looping past the timeout")
+	}
+}
+
+func TestScriptControlsWithoutStart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lua")
+	source := `
+controls = {{9, 1, "Up"}, {9, 0, "Off grid"}, {-247, 9, "Wraps"}, {9, 2, "Down"}}
+pad.every(1, function() error("timer ran") end)
+function start() error("started") end`
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	want := []Control{{launchpad.ButtonUp, "Up"}, {launchpad.ButtonDown, "Down"}}
+	if got := readControls(path); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
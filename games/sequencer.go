@@ -0,0 +1,206 @@
+package games
+
+import (
+	"sync"
+	"time"
+
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// NoteOut sends the notes of the music games to an external MIDI port.
+// It is nil when no port is configured; the games then run silently.
+var NoteOut func(msg midi.Message) error
+
+func sendNote(msg midi.Message) {
+	if NoteOut != nil {
+		NoteOut(msg)
+	}
+}
+
+func init() {
+	RegisterGame(func() Game { return &Sequencer{} })
+}
+
+const (
+	seqSteps = 16
+	seqPage  = 8
+	// seqChannel is the General MIDI drum channel 10.
+	seqChannel  = 9
+	seqVelocity = 100
+
+	seqResolution = 5 * time.Millisecond
+	seqDefaultBPM = 120
+	seqMinBPM     = 40
+	seqMaxBPM     = 240
+	seqBPMStep    = 5
+)
+
+// seqVoices are the General MIDI drum notes of the rows, bottom up, with
+// the colour of their steps.
+var seqVoices = [8]struct {
+	note  uint8
+	color uint8
+}{
+	{36, launchpad.ColorRed},    // bass drum
+	{38, launchpad.ColorOrange}, // snare
+	{42, launchpad.ColorYellow}, // closed hi-hat
+	{46, launchpad.ColorLime},   // open hi-hat
+	{39, launchpad.ColorGreen},  // clap
+	{45, launchpad.ColorCyan},   // low tom
+	{50, launchpad.ColorBlue},   // high tom
+	{49, launchpad.ColorPurple}, // crash
+}
+
+var (
+	seqPlayButton  = launchpad.PadPos{Row: 9, Col: 5}
+	seqClearButton = launchpad.PadPos{Row: 9, Col: 6}
+)
+
+// Sequencer is a 16 step drum machine. Rows are drum voices and columns
+// steps, eight to a page; pressing a pad toggles the step. The moving
+// playhead triggers the voices on the note output.
+type Sequencer struct {
+	ticker *Ticker
+
+	mu       sync.Mutex
+	screen   Screen
+	steps    [8][seqSteps]bool
+	page     int
+	playing  bool
+	bpm      int
+	step     int // the step last played
+	nextStep time.Time
+	sounding []uint8
+}
+
+func (g *Sequencer) Name() string { return "Sequencer" }
+
+func (g *Sequencer) Start(screen Screen) {
+	g.mu.Lock()
+	g.screen = screen
+	if g.bpm == 0 {
+		g.bpm = seqDefaultBPM
+	}
+	g.playing = false
+	g.draw()
+	g.mu.Unlock()
+
+	g.ticker = StartTicker(seqResolution, g.tick)
+}
+
+func (g *Sequencer) Press(pos launchpad.PadPos, down bool) {
+	if !down {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch pos {
+	case launchpad.ButtonLeft:
+		g.page = 0
+	case launchpad.ButtonRight:
+		g.page = 1
+	case launchpad.ButtonUp:
+		g.bpm = min(g.bpm+seqBPMStep, seqMaxBPM)
+	case launchpad.ButtonDown:
+		g.bpm = max(g.bpm-seqBPMStep, seqMinBPM)
+	case seqPlayButton:
+		g.playing = !g.playing
+		if g.playing {
+			g.step = seqSteps - 1
+			g.nextStep = time.Now()
+		} else {
+			g.release()
+		}
+	case seqClearButton:
+		g.steps = [8][seqSteps]bool{}
+	default:
+		if !onGrid(pos) {
+			return
+		}
+		step := g.page*seqPage + int(pos.Col) - 1
+		g.steps[pos.Row-1][step] = !g.steps[pos.Row-1][step]
+	}
+	g.draw()
+}
+
+func (g *Sequencer) Controls() []Control {
+	return []Control{
+		{launchpad.ButtonUp, "Faster"},
+		{launchpad.ButtonDown, "Slower"},
+		{launchpad.ButtonLeft, "Steps 1-8"},
+		{launchpad.ButtonRight, "Steps 9-16"},
+		{seqPlayButton, "Play/stop"},
+		{seqClearButton, "Clear"},
+	}
+}
+
+func (g *Sequencer) Stop() {
+	g.ticker.Stop()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.release()
+}
+
+// tick plays the next step once it is due. Steps are sixteenth notes.
+func (g *Sequencer) tick() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.playing || time.Now().Before(g.nextStep) {
+		return
+	}
+	g.nextStep = g.nextStep.Add(time.Minute / time.Duration(g.bpm*4))
+
+	g.release()
+	g.step = (g.step + 1) % seqSteps
+	for row, voice := range seqVoices {
+		if g.steps[row][g.step] {
+			sendNote(midi.NoteOn(seqChannel, voice.note, seqVelocity))
+			g.sounding = append(g.sounding, voice.note)
+		}
+	}
+	g.draw()
+}
+
+// release ends the notes of the last step.
+func (g *Sequencer) release() {
+	for _, note := range g.sounding {
+		sendNote(midi.NoteOff(seqChannel, note))
+	}
+	g.sounding = g.sounding[:0]
+}
+
+func (g *Sequencer) draw() {
+	var f launchpad.Frame
+	for row, voice := range seqVoices {
+		for col := range seqPage {
+			step := g.page*seqPage + col
+			pos := launchpad.PadPos{Row: uint8(row + 1), Col: uint8(col + 1)}
+			switch {
+			case g.steps[row][step]:
+				color := voice.color
+				if g.playing && step == g.step {
+					color = launchpad.ColorWhite
+				}
+				f.Set(pos, color)
+			case g.playing && step == g.step:
+				f.Set(pos, launchpad.ColorGreenDim)
+			}
+		}
+	}
+
+	pageColors := [2]uint8{launchpad.ColorBlueDim, launchpad.ColorBlueDim}
+	pageColors[g.page] = launchpad.ColorBlue
+	f.Set(launchpad.ButtonLeft, pageColors[0])
+	f.Set(launchpad.ButtonRight, pageColors[1])
+	f.Set(launchpad.ButtonUp, launchpad.ColorWhite)
+	f.Set(launchpad.ButtonDown, launchpad.ColorWhite)
+	if g.playing {
+		f.Set(seqPlayButton, launchpad.ColorGreen)
+	} else {
+		f.Set(seqPlayButton, launchpad.ColorRed)
+	}
+	f.Set(seqClearButton, launchpad.ColorOrange)
+	g.screen.Render(f)
+}
@@ -0,0 +1,198 @@
+package games
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/LaunchPadStreamer/events"
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+func init() {
+	RegisterGame(func() Game { return &Simon{} })
+}
+
+const simonInterval = 250 * time.Millisecond
+
+// Timings of the Simon phases, in ticks.
+const (
+	simonShowTicks  = 3 // a step of the sequence is lit
+	simonGapTicks   = 1 // dark gap between steps
+	simonPauseTicks = 4 // before the sequence is played
+	simonFailTicks  = 6 // red flash after a mistake
+)
+
+// simonColors are the bright and dim colours of the four quadrants, in
+// the order bottom left, bottom right, top left, top right.
+var simonColors = [4][2]uint8{
+	{launchpad.ColorGreen, launchpad.ColorGreenDim},
+	{launchpad.ColorRed, launchpad.ColorRedDim},
+	{launchpad.ColorBlue, launchpad.ColorBlueDim},
+	{launchpad.ColorOrange, launchpad.ColorOrangeDim},
+}
+
+type simonPhase int
+
+const (
+	simonPause simonPhase = iota
+	simonShow
+	simonInput
+	simonFail
+)
+
+// Simon plays an ever longer sequence of coloured quadrants which the
+// player repeats by pressing them. A mistake flashes the grid red and
+// starts over; the best streak is shown on the top row.
+type Simon struct {
+	ticker *Ticker
+
+	mu       sync.Mutex
+	screen   Screen
+	sequence []int
+	phase    simonPhase
+	ticks    int // ticks spent in the current phase
+	step     int // position in the sequence being shown or entered
+	held     int // quadrant held down by the player, or -1
+	best     int
+}
+
+func (g *Simon) Name() string { return "Simon" }
+
+func (g *Simon) Start(screen Screen) {
+	g.mu.Lock()
+	g.screen = screen
+	g.held = -1
+	g.sequence = nil
+	g.extend()
+	g.draw()
+	g.mu.Unlock()
+
+	g.ticker = StartTicker(simonInterval, g.tick)
+}
+
+func (g *Simon) Press(pos launchpad.PadPos, down bool) {
+	if !onGrid(pos) {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.phase != simonInput {
+		return
+	}
+
+	quadrant := simonQuadrant(pos)
+	if !down {
+		if g.held == quadrant {
+			g.held = -1
+			g.draw()
+		}
+		return
+	}
+
+	if quadrant != g.sequence[g.step] {
+		g.held = -1
+		g.enter(simonFail)
+		events.Publish(events.GameOver{Game: g.Name(), Score: len(g.sequence) - 1})
+		g.sequence = nil
+		g.draw()
+		return
+	}
+	g.held = quadrant
+	g.step++
+	if g.step == len(g.sequence) {
+		g.best = max(g.best, len(g.sequence))
+		events.Publish(events.ScoreChanged{Game: g.Name(), Score: len(g.sequence)})
+		g.extend()
+	}
+	g.draw()
+}
+
+func (g *Simon) Stop() {
+	g.ticker.Stop()
+}
+
+// extend adds a step to the sequence and plays it back from the start.
+func (g *Simon) extend() {
+	g.sequence = append(g.sequence, rand.IntN(len(simonColors)))
+	g.enter(simonPause)
+}
+
+func (g *Simon) enter(phase simonPhase) {
+	g.phase, g.ticks, g.step = phase, 0, 0
+}
+
+func (g *Simon) tick() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.ticks++
+	switch g.phase {
+	case simonPause:
+		if g.ticks >= simonPauseTicks {
+			g.held = -1
+			g.enter(simonShow)
+		}
+	case simonShow:
+		if g.ticks >= simonShowTicks+simonGapTicks {
+			g.step++
+			g.ticks = 0
+			if g.step == len(g.sequence) {
+				g.enter(simonInput)
+			}
+		}
+	case simonFail:
+		if g.ticks >= simonFailTicks {
+			fmt.Printf("Simon: best streak %d\n", g.best)
+			g.extend()
+		}
+	default:
+		return
+	}
+	g.draw()
+}
+
+func (g *Simon) draw() {
+	var f launchpad.Frame
+	if g.phase == simonFail {
+		f.Fill(launchpad.ColorRed)
+		g.screen.Render(f)
+		return
+	}
+
+	lit := g.held
+	if g.phase == simonShow && g.ticks < simonShowTicks {
+		lit = g.sequence[g.step]
+	}
+	for r := uint8(1); r <= 8; r++ {
+		for c := uint8(1); c <= 8; c++ {
+			pos := launchpad.PadPos{Row: r, Col: c}
+			q := simonQuadrant(pos)
+			pad := launchpad.Pad{Pos: pos, Color: simonColors[q][1]}
+			if q == lit {
+				pad.Color = simonColors[q][0]
+				if g.phase == simonShow {
+					pad.LightMode = launchpad.Pulsing
+				}
+			}
+			f.SetPad(pad)
+		}
+	}
+	for c := uint8(1); c <= min(uint8(g.best), 8); c++ {
+		f.Set(launchpad.PadPos{Row: 9, Col: c}, launchpad.ColorYellow)
+	}
+	g.screen.Render(f)
+}
+
+// simonQuadrant returns which 4x4 quadrant of the grid pos lies in.
+func simonQuadrant(pos launchpad.PadPos) int {
+	q := 0
+	if pos.Col > 4 {
+		q++
+	}
+	if pos.Row > 4 {
+		q += 2
+	}
+	return q
+}
@@ -0,0 +1,200 @@
+package games
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/LaunchPadStreamer/events"
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+func init() {
+	RegisterGame(func() Game { return &Snake{} })
+}
+
+const (
+	snakeInterval = 300 * time.Millisecond
+	// snakeOverTicks is how long the dead snake stays on screen before the
+	// next round starts.
+	snakeOverTicks = 6
+)
+
+var snakeFoodColors = []uint8{
+	launchpad.ColorRed,
+	launchpad.ColorOrange,
+	launchpad.ColorYellow,
+	launchpad.ColorCyan,
+	launchpad.ColorBlue,
+	launchpad.ColorPurple,
+	launchpad.ColorPink,
+}
+
+// Snake is the classic snake game on the 8x8 grid, steered with the arrow
+// buttons. Eating food grows the snake; running into a wall or itself ends
+// the round.
+type Snake struct {
+	ticker *Ticker
+
+	mu        sync.Mutex
+	screen    Screen
+	body      []launchpad.PadPos // head first
+	dir, next direction
+	food      launchpad.PadPos
+	foodColor uint8
+	eaten     int
+	over      int
+}
+
+// direction is a step on the grid in rows and columns.
+type direction struct{ row, col int }
+
+var (
+	dirUp    = direction{1, 0}
+	dirDown  = direction{-1, 0}
+	dirLeft  = direction{0, -1}
+	dirRight = direction{0, 1}
+)
+
+func (g *Snake) Name() string { return "Snake" }
+
+func (g *Snake) Start(screen Screen) {
+	g.mu.Lock()
+	g.screen = screen
+	g.reset()
+	g.draw()
+	g.mu.Unlock()
+
+	g.ticker = StartTicker(snakeInterval, g.tick)
+}
+
+func (g *Snake) Press(pos launchpad.PadPos, down bool) {
+	if !down {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var dir direction
+	switch pos {
+	case launchpad.ButtonUp:
+		dir = dirUp
+	case launchpad.ButtonDown:
+		dir = dirDown
+	case launchpad.ButtonLeft:
+		dir = dirLeft
+	case launchpad.ButtonRight:
+		dir = dirRight
+	default:
+		return
+	}
+	// Turning back onto the body would end the round at once.
+	if dir.row != -g.dir.row || dir.col != -g.dir.col {
+		g.next = dir
+	}
+}
+
+func (g *Snake) Controls() []Control {
+	return []Control{
+		{launchpad.ButtonUp, "Up"},
+		{launchpad.ButtonDown, "Down"},
+		{launchpad.ButtonLeft, "Left"},
+		{launchpad.ButtonRight, "Right"},
+	}
+}
+
+func (g *Snake) Stop() {
+	g.ticker.Stop()
+}
+
+func (g *Snake) reset() {
+	g.body = []launchpad.PadPos{{Row: 4, Col: 4}, {Row: 4, Col: 3}, {Row: 4, Col: 2}}
+	g.dir, g.next = dirRight, dirRight
+	g.eaten = 0
+	g.over = 0
+	g.placeFood()
+}
+
+func (g *Snake) tick() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.over > 0 {
+		g.over--
+		if g.over == 0 {
+			g.reset()
+		}
+		g.draw()
+		return
+	}
+
+	g.dir = g.next
+	head := launchpad.PadPos{
+		Row: uint8(int(g.body[0].Row) + g.dir.row),
+		Col: uint8(int(g.body[0].Col) + g.dir.col),
+	}
+	if !onGrid(head) || slices.Contains(g.body[:len(g.body)-1], head) {
+		g.over = snakeOverTicks
+		events.Publish(events.GameOver{Game: g.Name(), Score: g.eaten})
+		g.draw()
+		return
+	}
+
+	if head == g.food {
+		g.body = append([]launchpad.PadPos{head}, g.body...)
+		g.eaten++
+		events.Publish(events.ScoreChanged{Game: g.Name(), Score: g.eaten})
+		g.placeFood()
+	} else {
+		copy(g.body[1:], g.body)
+		g.body[0] = head
+	}
+	g.draw()
+}
+
+// placeFood puts food on a random free pad.
+func (g *Snake) placeFood() {
+	var free []launchpad.PadPos
+	for r := uint8(1); r <= 8; r++ {
+		for c := uint8(1); c <= 8; c++ {
+			pos := launchpad.PadPos{Row: r, Col: c}
+			if !slices.Contains(g.body, pos) {
+				free = append(free, pos)
+			}
+		}
+	}
+	if len(free) == 0 {
+		g.over = snakeOverTicks
+		events.Publish(events.GameOver{Game: g.Name(), Score: g.eaten, Won: true})
+		return
+	}
+	g.food = free[rand.IntN(len(free))]
+	g.foodColor = snakeFoodColors[rand.IntN(len(snakeFoodColors))]
+}
+
+func (g *Snake) draw() {
+	var f launchpad.Frame
+	bodyColor := launchpad.ColorGreen
+	if g.over > 0 {
+		bodyColor = launchpad.ColorRed
+	} else {
+		f.Set(g.food, g.foodColor)
+	}
+	for i, pos := range g.body {
+		if i == 0 && g.over == 0 {
+			f.Set(pos, launchpad.ColorGreenLight)
+			continue
+		}
+		f.Set(pos, bodyColor)
+	}
+	for _, button := range []launchpad.PadPos{launchpad.ButtonUp, launchpad.ButtonDown, launchpad.ButtonLeft, launchpad.ButtonRight} {
+		f.Set(button, launchpad.ColorWhite)
+	}
+	g.screen.Render(f)
+}
+
+// onGrid reports whether pos is one of the 8x8 grid pads.
+func onGrid(pos launchpad.PadPos) bool {
+	return pos.Row >= 1 && pos.Row <= 8 && pos.Col >= 1 && pos.Col <= 8
+}
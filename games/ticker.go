@@ -0,0 +1,49 @@
+package games
+
+import (
+	"sync"
+	"time"
+)
+
+// Ticker calls a function at a fixed interval for a game loop. Stop waits
+// for a running tick to return, so nothing ticks after a game's Stop.
+//
+// Stop must not be called from the tick function, and a game must not
+// hold a lock its tick function takes while calling it.
+type Ticker struct {
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// StartTicker calls tick every interval until Stop.
+func StartTicker(interval time.Duration, tick func()) *Ticker {
+	t := &Ticker{done: make(chan struct{})}
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-t.done:
+				return
+			case <-ticker.C:
+				tick()
+			}
+		}
+	}()
+	return t
+}
+
+// Stop ends the ticker. It is safe to call on a nil or stopped Ticker.
+func (t *Ticker) Stop() {
+	if t == nil {
+		return
+	}
+	select {
+	case <-t.done:
+	default:
+		close(t.done)
+	}
+	t.wg.Wait()
+}
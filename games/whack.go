@@ -0,0 +1,165 @@
+package games
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/LaunchPadStreamer/events"
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+func init() {
+	RegisterGame(func() Game { return &Whack{} })
+}
+
+const (
+	whackInterval  = 100 * time.Millisecond
+	whackLives     = 3
+	whackOverTicks = 10
+
+	// Ticks a mole stays up and ticks between new moles at the start.
+	// Both shrink by one tick every whackRampScore points.
+	whackUpTicks    = 15
+	whackSpawnTicks = 10
+	whackMinUp      = 5
+	whackMinSpawn   = 4
+	whackRampScore  = 3
+
+	// The top row shows the score on its first buttons and the lives left
+	// on the last ones.
+	whackScoreButtons = 5
+)
+
+// whackLevelColors colour the score bar; it starts over in the next colour
+// each time it fills up.
+var whackLevelColors = []uint8{
+	launchpad.ColorGreen,
+	launchpad.ColorYellow,
+	launchpad.ColorOrange,
+	launchpad.ColorPink,
+	launchpad.ColorPurple,
+}
+
+// Whack is whack-a-mole: moles light up on random pads for a short time
+// and score a point when pressed. A mole that gets away costs a life, and
+// the moles get quicker as the score grows.
+type Whack struct {
+	ticker *Ticker
+
+	mu     sync.Mutex
+	screen Screen
+	moles  map[launchpad.PadPos]int // ticks left before the mole escapes
+	spawn  int                      // ticks until the next mole
+	score  int
+	lives  int
+	over   int
+}
+
+func (g *Whack) Name() string { return "Whack-a-mole" }
+
+func (g *Whack) Start(screen Screen) {
+	g.mu.Lock()
+	g.screen = screen
+	g.reset()
+	g.draw()
+	g.mu.Unlock()
+
+	g.ticker = StartTicker(whackInterval, g.tick)
+}
+
+func (g *Whack) Press(pos launchpad.PadPos, down bool) {
+	if !down {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.moles[pos]; !ok || g.over > 0 {
+		return
+	}
+	delete(g.moles, pos)
+	g.score++
+	events.Publish(events.ScoreChanged{Game: g.Name(), Score: g.score})
+	g.draw()
+}
+
+func (g *Whack) Stop() {
+	g.ticker.Stop()
+}
+
+func (g *Whack) reset() {
+	g.moles = make(map[launchpad.PadPos]int)
+	g.spawn = whackSpawnTicks
+	g.score = 0
+	g.lives = whackLives
+	g.over = 0
+}
+
+func (g *Whack) tick() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.over > 0 {
+		g.over--
+		if g.over == 0 {
+			g.reset()
+		}
+		g.draw()
+		return
+	}
+
+	for pos, left := range g.moles {
+		if left > 1 {
+			g.moles[pos] = left - 1
+			continue
+		}
+		delete(g.moles, pos)
+		g.lives--
+	}
+	if g.lives <= 0 {
+		fmt.Printf("Whack-a-mole: score %d\n", g.score)
+		g.over = whackOverTicks
+		events.Publish(events.GameOver{Game: g.Name(), Score: g.score})
+		g.draw()
+		return
+	}
+
+	g.spawn--
+	if g.spawn <= 0 {
+		ramp := g.score / whackRampScore
+		g.spawn = max(whackSpawnTicks-ramp, whackMinSpawn)
+		pos := launchpad.PadPos{Row: uint8(rand.IntN(8) + 1), Col: uint8(rand.IntN(8) + 1)}
+		if _, ok := g.moles[pos]; !ok {
+			g.moles[pos] = max(whackUpTicks-ramp, whackMinUp)
+		}
+	}
+	g.draw()
+}
+
+func (g *Whack) draw() {
+	var f launchpad.Frame
+	if g.over > 0 {
+		f.Fill(launchpad.ColorRed)
+		g.screen.Render(f)
+		return
+	}
+
+	for pos, left := range g.moles {
+		color := launchpad.ColorGreen
+		if left <= whackMinUp/2 {
+			color = launchpad.ColorRed
+		}
+		f.Set(pos, color)
+	}
+
+	level := g.score / (whackScoreButtons + 1)
+	color := whackLevelColors[level%len(whackLevelColors)]
+	for c := range g.score % (whackScoreButtons + 1) {
+		f.Set(launchpad.PadPos{Row: 9, Col: uint8(c + 1)}, color)
+	}
+	for l := range g.lives {
+		f.Set(launchpad.PadPos{Row: 9, Col: uint8(8 - l)}, launchpad.ColorRed)
+	}
+	g.screen.Render(f)
+}
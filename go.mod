@@ -2,4 +2,7 @@ module github.com/codeneuss/LaunchPadStreamer
 
 go 1.25.5
 
-require gitlab.com/gomidi/midi/v2 v2.3.18 // indirect
+require (
+	github.com/yuin/gopher-lua v1.1.2
+	gitlab.com/gomidi/midi/v2 v2.3.18
+)
@@ -0,0 +1,168 @@
+// Package hooks runs configured actions when a game ends, so streamers
+// can wire up their own reactions without writing Go.
+//
+// Hooks are read from a JSON file holding a list of hooks:
+//
+//	[
+//		{"on": "win", "game": "Pong", "run": "notify-send 'Pong won'"},
+//		{"on": "highscore", "webhook": "http://localhost:8080/highscore"}
+//	]
+//
+// on is one of win, loss or highscore. In multiplayer games win fires for
+// the winner and loss for every other player; a draw is a loss for all of
+// them. A high score is a final score above
+// the best of the earlier rounds of the game in this session; the first
+// round only sets the score to beat. game limits the hook to one game;
+// without it the hook fires for all of them. run is a shell command that
+// gets the outcome in the LPS_GAME, LPS_PLAYER and LPS_SCORE environment
+// variables; webhook is a URL the outcome is POSTed to as JSON. A hook
+// may have both.
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/codeneuss/LaunchPadStreamer/events"
+)
+
+// Triggers a hook can fire on.
+const (
+	Win       = "win"
+	Loss      = "loss"
+	HighScore = "highscore"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// Hook is an action to run on a game outcome.
+type Hook struct {
+	On      string `json:"on"`
+	Game    string `json:"game,omitempty"`
+	Run     string `json:"run,omitempty"`
+	Webhook string `json:"webhook,omitempty"`
+}
+
+// Load reads the hooks from the JSON file at path.
+func Load(path string) ([]Hook, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var hooks []Hook
+	if err := json.Unmarshal(data, &hooks); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for i, h := range hooks {
+		switch h.On {
+		case Win, Loss, HighScore:
+		default:
+			return nil, fmt.Errorf("%s: hook %d: unknown trigger %q", path, i+1, h.On)
+		}
+		if h.Run == "" && h.Webhook == "" {
+			return nil, fmt.Errorf("%s: hook %d: no run or webhook action", path, i+1)
+		}
+	}
+	return hooks, nil
+}
+
+// outcome is what the actions are told about a finished game.
+type outcome struct {
+	Trigger string `json:"trigger"`
+	Game    string `json:"game"`
+	Player  int    `json:"player"`
+	Score   int    `json:"score"`
+}
+
+// Start runs hooks for every game published on the Default event bus
+// until the returned function is called. Actions run in the background;
+// their errors are printed.
+func Start(hooks []Hook) (stop func()) {
+	best := make(scores)
+	return events.Subscribe(func(e events.GameOver) {
+		for _, trigger := range best.triggers(e) {
+			for _, h := range matching(hooks, trigger, e.Game) {
+				go h.fire(outcome{Trigger: trigger, Game: e.Game, Player: e.Player, Score: e.Score})
+			}
+		}
+	})
+}
+
+// scores holds the best score of each game this session.
+type scores map[string]int
+
+// triggers returns what a finished game fires: win or loss, and highscore
+// if it beat the best of the earlier rounds. The first round only sets
+// the score to beat.
+func (s scores) triggers(e events.GameOver) []string {
+	triggers := []string{Loss}
+	if e.Won {
+		triggers[0] = Win
+	}
+	best, played := s[e.Game]
+	if played && e.Score > best {
+		triggers = append(triggers, HighScore)
+	}
+	if !played || e.Score > best {
+		s[e.Game] = e.Score
+	}
+	return triggers
+}
+
+// matching returns the hooks that fire on trigger in game.
+func matching(hooks []Hook, trigger, game string) []Hook {
+	var out []Hook
+	for _, h := range hooks {
+		if h.On == trigger && (h.Game == "" || h.Game == game) {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+func (h Hook) fire(o outcome) {
+	if h.Run != "" {
+		if err := runCommand(h.Run, o); err != nil {
+			fmt.Printf("Hook Error: %s: %v\n", h.Run, err)
+		}
+	}
+	if h.Webhook != "" {
+		if err := post(h.Webhook, o); err != nil {
+			fmt.Printf("Hook Error: %s: %v\n", h.Webhook, err)
+		}
+	}
+}
+
+func runCommand(command string, o outcome) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(),
+		"LPS_GAME="+o.Game,
+		"LPS_PLAYER="+strconv.Itoa(o.Player),
+		"LPS_SCORE="+strconv.Itoa(o.Score),
+	)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+func post(url string, o outcome) error {
+	body, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+	client := http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/codeneuss/LaunchPadStreamer/events"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    []Hook
+		wantErr string
+	}{
+		{
+			name: "valid",
+			json: `[{"on": "win", "game": "Pong", "run": "true"}, {"on": "highscore", "webhook": "http://localhost/"}]`,
+			want: []Hook{{On: Win, Game: "Pong", Run: "true"}, {On: HighScore, Webhook: "http://localhost/"}},
+		},
+		{name: "empty", json: `[]`, want: []Hook{}},
+		{name: "unknown trigger", json: `[{"on": "draw", "run": "true"}]`, wantErr: `unknown trigger "draw"`},
+		{name: "no action", json: `[{"on": "loss"}]`, wantErr: "no run or webhook action"},
+		{name: "not a list", json: `{"on": "loss"}`, wantErr: "cannot unmarshal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "hooks.json")
+			if err := os.WriteFile(path, []byte(tt.json), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Load(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("got %v, want a not exist error", err)
+	}
+}
+
+func TestTriggers(t *testing.T) {
+	rounds := []struct {
+		event events.GameOver
+		want  []string
+	}{
+		{events.GameOver{Game: "Snake", Score: 5}, []string{Loss}},
+		{events.GameOver{Game: "Snake", Score: 3}, []string{Loss}},
+		{events.GameOver{Game: "Snake", Score: 5}, []string{Loss}},
+		{events.GameOver{Game: "Snake", Score: 6}, []string{Loss, HighScore}},
+		{events.GameOver{Game: "Simon", Score: 2}, []string{Loss}},
+		{events.GameOver{Game: "Snake", Score: 61, Won: true}, []string{Win, HighScore}},
+		{events.GameOver{Game: "Pong", Player: 1, Score: 4, Won: true}, []string{Win}},
+		{events.GameOver{Game: "Pong", Player: 2, Score: 2}, []string{Loss}},
+		{events.GameOver{Game: "Pong", Player: 2, Score: 4, Won: true}, []string{Win}},
+		{events.GameOver{Game: "Pong", Player: 1, Score: 3}, []string{Loss}},
+		{events.GameOver{Game: "Connect Four", Player: 1}, []string{Loss}},
+		{events.GameOver{Game: "Connect Four", Player: 2}, []string{Loss}},
+	}
+	best := make(scores)
+	for i, r := range rounds {
+		if got := best.triggers(r.event); !slices.Equal(got, r.want) {
+			t.Errorf("round %d %+v: got %v, want %v", i+1, r.event, got, r.want)
+		}
+	}
+}
+
+func TestMatching(t *testing.T) {
+	hooks := []Hook{
+		{On: Win, Run: "any win"},
+		{On: Win, Game: "Pong", Run: "pong win"},
+		{On: Loss, Game: "Pong", Run: "pong loss"},
+		{On: HighScore, Game: "Snake", Run: "snake high score"},
+	}
+	tests := []struct {
+		trigger, game string
+		want          []string
+	}{
+		{Win, "Pong", []string{"any win", "pong win"}},
+		{Win, "Connect Four", []string{"any win"}},
+		{Loss, "Pong", []string{"pong loss"}},
+		{Loss, "Snake", nil},
+		{HighScore, "Snake", []string{"snake high score"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, h := range matching(hooks, tt.trigger, tt.game) {
+			got = append(got, h.Run)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s in %s: got %v, want %v", tt.trigger, tt.game, got, tt.want)
+		}
+	}
+}
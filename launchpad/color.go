@@ -0,0 +1,62 @@
+package launchpad
+
+// Palette indices of commonly used colours. The Launchpad has a fixed
+// palette of 128 entries; see the programmer's reference for the full
+// table.
+const (
+	ColorOff         uint8 = 0
+	ColorWhite       uint8 = 3
+	ColorRed         uint8 = 5
+	ColorRedDim      uint8 = 6
+	ColorRedLight    uint8 = 7
+	ColorOrange      uint8 = 9
+	ColorOrangeDim   uint8 = 10
+	ColorYellow      uint8 = 13
+	ColorYellowLight uint8 = 14
+	ColorLime        uint8 = 17
+	ColorGreen       uint8 = 21
+	ColorGreenDim    uint8 = 22
+	ColorGreenLight  uint8 = 23
+	ColorMint        uint8 = 29
+	ColorCyan        uint8 = 37
+	ColorCyanLight   uint8 = 38
+	ColorSky         uint8 = 41
+	ColorBlue        uint8 = 45
+	ColorBlueDim     uint8 = 46
+	ColorBlueLight   uint8 = 47
+	ColorPurple      uint8 = 49
+	ColorPurpleLight uint8 = 51
+	ColorMagenta     uint8 = 53
+	ColorPink        uint8 = 57
+	ColorPinkLight   uint8 = 58
+	ColorHotPink     uint8 = 56
+)
+
+// modeColors lists palette substitutions for the animated light modes.
+// Flashing and pulsing both spend part of their cycle dark, which makes the
+// dim shades hard to tell from off, so those are lifted to the full
+// brightness entry of the same hue.
+var modeColors = map[LightMode]map[uint8]uint8{
+	Blinking: {
+		ColorRedDim:    ColorRed,
+		ColorOrangeDim: ColorOrange,
+		ColorGreenDim:  ColorGreen,
+		ColorBlueDim:   ColorBlue,
+	},
+	Pulsing: {
+		ColorRedDim:    ColorRed,
+		ColorOrangeDim: ColorOrange,
+		ColorGreenDim:  ColorGreen,
+		ColorBlueDim:   ColorBlue,
+		ColorCyanLight: ColorCyan,
+	},
+}
+
+// paletteIndex returns the palette index to send so that color looks as
+// intended in mode.
+func paletteIndex(color uint8, mode LightMode) uint8 {
+	if index, ok := modeColors[mode][color]; ok {
+		return index
+	}
+	return color
+}
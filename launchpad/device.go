@@ -0,0 +1,268 @@
+// Package launchpad drives a Novation Launchpad Mini MK3 in programmer
+// mode: it addresses pads by row and column, tracks their LED state and
+// reports presses.
+//
+// The package does not register a MIDI driver; import one (for example
+// gitlab.com/gomidi/midi/v2/drivers/rtmididrv) in the main package.
+package launchpad
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/codeneuss/LaunchPadStreamer/events"
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers"
+)
+
+// Port names the Mini MK3 registers for its programmer mode interface.
+const (
+	OutPortName = "LPMiniMK3 MIDI In"
+	InPortName  = "LPMiniMK3 MIDI Out"
+)
+
+// programmerMode switches the device from the Live layout to programmer
+// mode, where every pad and button reports and accepts its own key.
+var programmerMode = []byte{0xF0, 0x00, 0x20, 0x29, 0x02, 0x0D, 0x0E, 0x01, 0xF7}
+
+// closeTimeout is how long Close waits for queued LED updates.
+const closeTimeout = time.Second
+
+// Device is a connected Launchpad. It is safe for concurrent use.
+//
+// LED updates are written to the device in the background, in the order
+// they were made. An error writing one is returned by the next call that
+// sends.
+type Device struct {
+	in   drivers.In
+	stop func()
+
+	mu      sync.Mutex
+	out     *output
+	frame   Frame
+	safe    safeMode
+	history history
+}
+
+// Open connects to the Launchpad ports and puts the device into
+// programmer mode.
+func Open() (*Device, error) {
+	out, err := midi.FindOutPort(OutPortName)
+	if err != nil {
+		return nil, err
+	}
+	send, err := midi.SendTo(out)
+	if err != nil {
+		return nil, err
+	}
+	in, err := midi.FindInPort(InPortName)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Device{in: in, out: newOutput(send, out)}
+	d.out.push(programmerMode)
+	d.out.wait(closeTimeout)
+	if err := d.out.takeErr(); err != nil {
+		d.out.stop()
+		return nil, fmt.Errorf("entering programmer mode: %w", err)
+	}
+	return d, nil
+}
+
+// New returns a Device writing LED messages to send. It has no input
+// port, so Listen is a no-op; use it to drive a Launchpad through a port
+// opened elsewhere.
+func New(send func(msg midi.Message) error) *Device {
+	return &Device{out: newOutput(send, nil)}
+}
+
+// Listen calls handler for every pad press and release until Close.
+func (d *Device) Listen(handler func(pos PadPos, down bool)) error {
+	if d.in == nil {
+		return nil
+	}
+	stop, err := midi.ListenTo(d.in, func(msg midi.Message, ts int32) {
+		var channel, key, value uint8
+		switch {
+		case msg.GetNoteOn(&channel, &key, &value):
+			handler(PadPosFromKey(key), value > 0)
+		case msg.GetNoteOff(&channel, &key, &value):
+			handler(PadPosFromKey(key), false)
+		case msg.GetControlChange(&channel, &key, &value):
+			handler(PadPosFromKey(key), value > 0)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	d.stop = stop
+	return nil
+}
+
+// Close stops listening for input and closes the output once the queued
+// LED updates are written, waiting at most a second for them.
+func (d *Device) Close() {
+	if d.stop != nil {
+		d.stop()
+	}
+	d.mu.Lock()
+	out := d.out
+	d.mu.Unlock()
+	out.wait(closeTimeout)
+	out.stop()
+}
+
+// Pad returns the last state sent to the pad at pos.
+func (d *Device) Pad(pos PadPos) Pad {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.frame.Pad(pos)
+}
+
+// Frame returns the LED state currently shown on the device.
+func (d *Device) Frame() Frame {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.frame
+}
+
+// SendNote lights the pad, or switches it off if on is false. In safe mode
+// the pad goes through the same limits as a rendered frame.
+func (d *Device) SendNote(on bool, pad Pad) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !on {
+		pad = NewPad(pad.Pos)
+	}
+	if d.safe.on {
+		return d.renderSafePads([]Pad{pad})
+	}
+	d.frame.SetPad(pad)
+	d.flushed(1)
+	var err error
+	for _, msg := range ledMessages(pad) {
+		if werr := d.write(msg); err == nil {
+			err = werr
+		}
+	}
+	return err
+}
+
+// SetPadFlash makes the pad at pos alternate between colorA and colorB.
+func (d *Device) SetPadFlash(pos PadPos, colorA, colorB uint8) error {
+	return d.SendNote(On, Pad{Pos: pos, Color: colorA, FlashColor: colorB, LightMode: Blinking})
+}
+
+// Render brings the device in line with f. Only pads that differ from the
+// current state are sent, so games can redraw their whole frame every tick.
+// In safe mode flashes may be softened or held back; see SetSafeMode.
+func (d *Device) Render(f Frame) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.safe.on {
+		return d.renderSafe(f)
+	}
+	return d.render(f, false)
+}
+
+// Clear switches every pad and button off, regardless of the state the
+// device is believed to be in.
+func (d *Device) Clear() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.render(Frame{}, true)
+}
+
+// FlushBatch lights all pads with a single LED lighting SysEx, so a full
+// redraw reaches the device at once instead of pad by pad. In safe mode
+// the pads go through the same limits as a rendered frame.
+func (d *Device) FlushBatch(pads []Pad) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.safe.on {
+		return d.renderSafePads(pads)
+	}
+	return d.flush(pads)
+}
+
+func (d *Device) render(f Frame, force bool) error {
+	var changed []Pad
+	for r := range uint8(9) {
+		for c := range uint8(9) {
+			if force || f.cells[r][c] != d.frame.cells[r][c] {
+				changed = append(changed, f.Pad(PadPos{r + 1, c + 1}))
+			}
+		}
+	}
+	return d.flush(changed)
+}
+
+func (d *Device) flush(pads []Pad) error {
+	if len(pads) == 0 {
+		return nil
+	}
+	err := d.write(ledSysEx(pads))
+	for _, pad := range pads {
+		d.frame.SetPad(pad)
+	}
+	d.flushed(len(pads))
+	return err
+}
+
+// flushed records the frame after an update of n pads in the history and
+// publishes it. The caller holds d.mu.
+func (d *Device) flushed(n int) {
+	d.history.add(d.frame)
+	events.Publish(events.FrameFlushed{Time: time.Now(), Pads: n})
+}
+
+// write queues msg for the device and returns the error of an earlier
+// write, if any. The caller holds d.mu.
+func (d *Device) write(msg midi.Message) error {
+	return d.out.push(msg)
+}
+
+// ledSysEx encodes pads as one LED lighting message (command 0x03). Each
+// pad is a colour spec of lighting type, LED index and colour data.
+func ledSysEx(pads []Pad) []byte {
+	msg := []byte{0xF0, 0x00, 0x20, 0x29, 0x02, 0x0D, 0x03}
+	for _, pad := range pads {
+		if !pad.Pos.valid() {
+			continue
+		}
+		color := paletteIndex(pad.Color, pad.LightMode)
+		switch pad.LightMode {
+		case Blinking:
+			flash := paletteIndex(pad.FlashColor, pad.LightMode)
+			msg = append(msg, 1, pad.Pos.Key(), flash, color)
+		case Pulsing:
+			msg = append(msg, 2, pad.Pos.Key(), color)
+		default:
+			msg = append(msg, 0, pad.Pos.Key(), color)
+		}
+	}
+	return append(msg, 0xF7)
+}
+
+// ledMessages encodes a pad update. Grid pads are addressed by note, the
+// top row and right column buttons by control change. A flashing pad
+// alternates between the colour on the static channel and the one on the
+// flash channel, so it takes two messages in that order.
+//
+// The colour is mapped through paletteIndex here rather than when it is
+// stored, so frames keep the colour the caller asked for.
+func ledMessages(pad Pad) []midi.Message {
+	message := func(mode LightMode, color uint8) midi.Message {
+		color = paletteIndex(color, pad.LightMode)
+		if pad.Pos.IsButton() {
+			return midi.ControlChange(mode.channel(), pad.Pos.Key(), color)
+		}
+		return midi.NoteOn(mode.channel(), pad.Pos.Key(), color)
+	}
+	if pad.LightMode == Blinking {
+		return []midi.Message{message(Permanent, pad.FlashColor), message(Blinking, pad.Color)}
+	}
+	return []midi.Message{message(pad.LightMode, pad.Color)}
+}
@@ -0,0 +1,227 @@
+package launchpad
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codeneuss/LaunchPadStreamer/events"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// recorder collects the messages a Device sends, hex encoded.
+type recorder struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (r *recorder) send(msg midi.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, fmt.Sprintf("%x", []byte(msg)))
+	return nil
+}
+
+// written returns the messages d has written so far, once its queue is
+// empty.
+func (r *recorder) written(t *testing.T, d *Device) []string {
+	t.Helper()
+	if !d.out.wait(time.Second) {
+		t.Fatal("output did not drain")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.sent...)
+}
+
+func TestSendNote(t *testing.T) {
+	grid := PadPos{Row: 1, Col: 1}
+	tests := []struct {
+		name string
+		on   bool
+		pad  Pad
+		want []string
+	}{
+		{"static", On, Pad{Pos: grid, Color: ColorRed, LightMode: Permanent}, []string{"900b05"}},
+		{"pulse", On, Pad{Pos: grid, Color: ColorRed, LightMode: Pulsing}, []string{"920b05"}},
+		{"flash", On, Pad{Pos: grid, Color: ColorRed, FlashColor: ColorWhite, LightMode: Blinking}, []string{"900b03", "910b05"}},
+		{"button", On, Pad{Pos: ButtonUp, Color: ColorRed, LightMode: Permanent}, []string{"b05b05"}},
+		{"flashing button", On, Pad{Pos: ButtonUp, Color: ColorRed, FlashColor: ColorWhite, LightMode: Blinking}, []string{"b05b03", "b15b05"}},
+		{"off", Off, Pad{Pos: grid, Color: ColorRed, LightMode: Pulsing}, []string{"900b00"}},
+		{"button off", Off, Pad{Pos: ButtonUp, Color: ColorRed, LightMode: Blinking}, []string{"b05b00"}},
+		{"mode colour", On, Pad{Pos: grid, Color: ColorRedDim, LightMode: Pulsing}, []string{"920b05"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r recorder
+			d := New(r.send)
+			if err := d.SendNote(tt.on, tt.pad); err != nil {
+				t.Fatal(err)
+			}
+			if sent := r.written(t, d); fmt.Sprint(sent) != fmt.Sprint(tt.want) {
+				t.Errorf("sent %v, want %v", sent, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetPadFlash(t *testing.T) {
+	var r recorder
+	d := New(r.send)
+	if err := d.SetPadFlash(PadPos{Row: 8, Col: 8}, ColorRed, ColorWhite); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"905803", "915805"}
+	if sent := r.written(t, d); fmt.Sprint(sent) != fmt.Sprint(want) {
+		t.Errorf("sent %v, want %v", sent, want)
+	}
+	if got := d.Pad(PadPos{Row: 8, Col: 8}); got.Color != ColorRed || got.FlashColor != ColorWhite || got.LightMode != Blinking {
+		t.Errorf("pad state %+v", got)
+	}
+}
+
+func TestRenderSysEx(t *testing.T) {
+	tests := []struct {
+		name string
+		draw func(f *Frame)
+		want string
+	}{
+		{"static", func(f *Frame) { f.Set(PadPos{Row: 1, Col: 1}, ColorRed) }, "f0002029020d03000b05f7"},
+		{"flash", func(f *Frame) { f.SetFlash(PadPos{Row: 1, Col: 1}, ColorRed, ColorWhite) }, "f0002029020d03010b0305f7"},
+		{"pulse", func(f *Frame) {
+			f.SetPad(Pad{Pos: PadPos{Row: 1, Col: 1}, Color: ColorRed, LightMode: Pulsing})
+		}, "f0002029020d03020b05f7"},
+		{"button", func(f *Frame) { f.Set(ButtonUp, ColorWhite) }, "f0002029020d03005b03f7"},
+		{"several", func(f *Frame) {
+			f.Set(PadPos{Row: 1, Col: 2}, ColorRed)
+			f.Set(PadPos{Row: 8, Col: 8}, ColorGreen)
+		}, "f0002029020d03000c05005815f7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r recorder
+			d := New(r.send)
+			var f Frame
+			tt.draw(&f)
+			if err := d.Render(f); err != nil {
+				t.Fatal(err)
+			}
+			if sent := r.written(t, d); len(sent) != 1 || sent[0] != tt.want {
+				t.Errorf("sent %v, want [%s]", sent, tt.want)
+			}
+			if d.Frame() != f {
+				t.Error("device frame differs from the rendered frame")
+			}
+		})
+	}
+}
+
+func TestRenderSendsChangesOnly(t *testing.T) {
+	var r recorder
+	d := New(r.send)
+	var f Frame
+	f.Set(PadPos{Row: 1, Col: 1}, ColorRed)
+	d.Render(f)
+	d.Render(f)
+	if sent := r.written(t, d); len(sent) != 1 {
+		t.Fatalf("sent %d messages for an unchanged frame, want 1", len(sent))
+	}
+
+	f.Set(PadPos{Row: 1, Col: 1}, ColorOff)
+	d.Render(f)
+	if sent, want := r.written(t, d), "f0002029020d03000b00f7"; len(sent) != 2 || sent[1] != want {
+		t.Errorf("sent %v, want %s last", sent, want)
+	}
+}
+
+func TestClear(t *testing.T) {
+	var r recorder
+	d := New(r.send)
+	if err := d.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	// Every pad and button goes out as static off: 7 header bytes, 81
+	// colour specs of 3 bytes and the end byte.
+	if sent := r.written(t, d); len(sent) != 1 || len(sent[0]) != 2*(7+81*3+1) {
+		t.Fatalf("sent %v", sent)
+	}
+}
+
+// Every way of lighting pads tells FrameFlushed subscribers.
+func TestFrameFlushed(t *testing.T) {
+	pos := PadPos{Row: 2, Col: 2}
+	tests := []struct {
+		name string
+		draw func(d *Device)
+		pads int
+	}{
+		{"SendNote", func(d *Device) { d.SendNote(On, Pad{Pos: pos, Color: ColorRed}) }, 1},
+		{"SetPadFlash", func(d *Device) { d.SetPadFlash(pos, ColorRed, ColorWhite) }, 1},
+		{"Render", func(d *Device) {
+			var f Frame
+			f.Set(pos, ColorRed)
+			d.Render(f)
+		}, 1},
+		{"FlushBatch", func(d *Device) {
+			d.FlushBatch([]Pad{{Pos: pos, Color: ColorRed}, {Pos: ButtonUp, Color: ColorRed}})
+		}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			published := make(chan events.FrameFlushed, 1)
+			unsubscribe := events.Subscribe(func(e events.FrameFlushed) { published <- e })
+			defer unsubscribe()
+
+			var r recorder
+			tt.draw(New(r.send))
+			select {
+			case e := <-published:
+				if e.Pads != tt.pads {
+					t.Errorf("published %d pads, want %d", e.Pads, tt.pads)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("FrameFlushed not published")
+			}
+		})
+	}
+}
+
+func TestWriteErrorReturnedByNextCall(t *testing.T) {
+	errSend := fmt.Errorf("port gone")
+	d := New(func(midi.Message) error { return errSend })
+	defer d.Close()
+
+	pad := Pad{Pos: PadPos{Row: 1, Col: 1}, Color: ColorRed}
+	d.SendNote(On, pad)
+	d.out.wait(time.Second)
+	if err := d.SendNote(On, pad); err != errSend {
+		t.Errorf("got %v, want %v", err, errSend)
+	}
+}
+
+// A write that never returns must not keep the device locked.
+func TestBlockedWriteDoesNotLock(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	d := New(func(midi.Message) error { <-block; return nil })
+
+	var f Frame
+	f.Set(PadPos{Row: 1, Col: 1}, ColorRed)
+	done := make(chan struct{})
+	go func() {
+		d.Render(f)
+		d.Render(Frame{})
+		d.Frame()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("device stayed locked while a write was blocked")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if d.out.stalled(10*time.Millisecond) == 0 {
+		t.Error("blocked write not reported as stalled")
+	}
+}
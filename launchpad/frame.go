@@ -0,0 +1,65 @@
+package launchpad
+
+// Frame is a full image of the device LEDs that games draw into before
+// handing it to Device.Render. Pads are addressed like PadPos, so row 9 and
+// column 9 hold the control buttons. The zero value is a dark frame.
+//
+// A frame holds each pad's light mode along with its colours, so a game
+// that redraws a flashing or pulsing pad every tick keeps it animating;
+// the device is only sent a pad when its colour or mode changes.
+type Frame struct {
+	cells [9][9]cell
+}
+
+type cell struct {
+	color      uint8
+	flashColor uint8
+	lightMode  LightMode
+}
+
+// Set lights the pad at pos with a static colour.
+func (f *Frame) Set(pos PadPos, color uint8) {
+	f.SetPad(Pad{Pos: pos, Color: color, LightMode: Permanent})
+}
+
+// SetPad stores pad at its position. Positions outside the grid are
+// ignored so callers can draw partially off-screen shapes.
+func (f *Frame) SetPad(pad Pad) {
+	if !pad.Pos.valid() {
+		return
+	}
+	c := cell{color: pad.Color, lightMode: pad.LightMode}
+	if pad.LightMode == Blinking {
+		c.flashColor = pad.FlashColor
+	}
+	f.cells[pad.Pos.Row-1][pad.Pos.Col-1] = c
+}
+
+// SetFlash makes the pad at pos alternate between colorA and colorB.
+func (f *Frame) SetFlash(pos PadPos, colorA, colorB uint8) {
+	f.SetPad(Pad{Pos: pos, Color: colorA, FlashColor: colorB, LightMode: Blinking})
+}
+
+// Pad returns the pad at pos.
+func (f *Frame) Pad(pos PadPos) Pad {
+	pad := NewPad(pos)
+	if pos.valid() {
+		c := f.cells[pos.Row-1][pos.Col-1]
+		pad.Color, pad.FlashColor, pad.LightMode = c.color, c.flashColor, c.lightMode
+	}
+	return pad
+}
+
+// Fill sets every grid pad to color, leaving the control buttons alone.
+func (f *Frame) Fill(color uint8) {
+	for r := range uint8(8) {
+		for c := range uint8(8) {
+			f.Set(PadPos{r + 1, c + 1}, color)
+		}
+	}
+}
+
+// valid reports whether the position addresses a pad or button.
+func (p PadPos) valid() bool {
+	return p.Row >= 1 && p.Row <= 9 && p.Col >= 1 && p.Col <= 9
+}
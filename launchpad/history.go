@@ -0,0 +1,49 @@
+package launchpad
+
+import "time"
+
+// historySize is how many frames a Device keeps, about a minute at ten
+// updates a second.
+const historySize = 600
+
+// Snapshot is the state of the device LEDs from a point in time on.
+type Snapshot struct {
+	Time  time.Time
+	Frame Frame
+}
+
+// history is a ring buffer of the most recent snapshots.
+type history struct {
+	buf  []Snapshot
+	next int
+}
+
+func (h *history) add(f Frame) {
+	s := Snapshot{Time: time.Now(), Frame: f}
+	if len(h.buf) < historySize {
+		h.buf = append(h.buf, s)
+		return
+	}
+	h.buf[h.next] = s
+	h.next = (h.next + 1) % historySize
+}
+
+// last returns up to n of the most recent snapshots, oldest first.
+func (h *history) last(n int) []Snapshot {
+	n = min(max(n, 0), len(h.buf))
+	out := make([]Snapshot, n)
+	start := h.next + len(h.buf) - n
+	for i := range out {
+		out[i] = h.buf[(start+i)%len(h.buf)]
+	}
+	return out
+}
+
+// History returns up to n of the most recent frames shown on the device,
+// oldest first, each with the time it was sent. The device keeps the last
+// 600 frames.
+func (d *Device) History(n int) []Snapshot {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.history.last(n)
+}
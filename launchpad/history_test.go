@@ -0,0 +1,69 @@
+package launchpad
+
+import "testing"
+
+// numbered returns a frame that encodes i in two pads.
+func numbered(i int) Frame {
+	var f Frame
+	f.Set(PadPos{Row: 1, Col: 1}, uint8(i%128))
+	f.Set(PadPos{Row: 1, Col: 2}, uint8(i/128))
+	return f
+}
+
+func number(f Frame) int {
+	return int(f.Pad(PadPos{Row: 1, Col: 1}).Color) + 128*int(f.Pad(PadPos{Row: 1, Col: 2}).Color)
+}
+
+func TestHistoryLast(t *testing.T) {
+	tests := []struct {
+		name  string
+		added int
+		n     int
+		want  []int
+	}{
+		{"empty", 0, 5, nil},
+		{"fewer than asked", 3, 5, []int{0, 1, 2}},
+		{"most recent", 10, 3, []int{7, 8, 9}},
+		{"none asked", 10, 0, nil},
+		{"negative", 10, -1, nil},
+		{"full", historySize, 2, []int{historySize - 2, historySize - 1}},
+		{"wrapped", historySize + 5, 7, []int{598, 599, 600, 601, 602, 603, 604}},
+		{"wrapped twice", 2*historySize + 1, 2, []int{2*historySize - 1, 2 * historySize}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h history
+			for i := range tt.added {
+				h.add(numbered(i))
+			}
+			got := h.last(tt.n)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d snapshots, want %d", len(got), len(tt.want))
+			}
+			for i, s := range got {
+				if number(s.Frame) != tt.want[i] {
+					t.Errorf("snapshot %d is frame %d, want %d", i, number(s.Frame), tt.want[i])
+				}
+				if i > 0 && s.Time.Before(got[i-1].Time) {
+					t.Errorf("snapshot %d is older than the one before", i)
+				}
+			}
+		})
+	}
+}
+
+func TestHistoryKeepsAll(t *testing.T) {
+	var h history
+	for i := range historySize + 100 {
+		h.add(numbered(i))
+	}
+	got := h.last(historySize + 50)
+	if len(got) != historySize {
+		t.Fatalf("got %d snapshots, want %d", len(got), historySize)
+	}
+	for i, s := range got {
+		if want := 100 + i; number(s.Frame) != want {
+			t.Fatalf("snapshot %d is frame %d, want %d", i, number(s.Frame), want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package launchpad
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers"
+)
+
+// output writes messages to the device in order on a goroutine of its
+// own. Callers only queue, so no Device lock is held while the driver is
+// busy, and a write that never returns can be abandoned together with its
+// port.
+type output struct {
+	send func(msg midi.Message) error
+	// port is closed by the output's goroutine once it stops, so it is
+	// never closed while a write to it is in progress. It is nil for
+	// devices created with New.
+	port drivers.Out
+
+	// sending holds the UnixNano time the write in progress started, or
+	// zero. The watchdog reads it to spot a blocked driver.
+	sending atomic.Int64
+
+	mu      sync.Mutex
+	idle    *sync.Cond
+	queue   []midi.Message
+	busy    bool
+	err     error
+	wake    chan struct{}
+	stopped bool
+}
+
+func newOutput(send func(msg midi.Message) error, port drivers.Out) *output {
+	o := &output{send: send, port: port, wake: make(chan struct{}, 1)}
+	o.idle = sync.NewCond(&o.mu)
+	go o.run()
+	return o
+}
+
+// push queues msg and returns the first error of the writes since the
+// last call, if any.
+func (o *output) push(msg midi.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.stopped {
+		o.queue = append(o.queue, msg)
+		select {
+		case o.wake <- struct{}{}:
+		default:
+		}
+	}
+	err := o.err
+	o.err = nil
+	return err
+}
+
+// takeErr returns and clears the first error of the writes since the
+// last call.
+func (o *output) takeErr() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	err := o.err
+	o.err = nil
+	return err
+}
+
+// stalled returns the UnixNano start time of the write in progress if it
+// has taken longer than timeout, or zero.
+func (o *output) stalled(timeout time.Duration) int64 {
+	started := o.sending.Load()
+	if started == 0 || time.Since(time.Unix(0, started)) < timeout {
+		return 0
+	}
+	return started
+}
+
+// wait blocks until every queued message is written or timeout passes,
+// and reports whether the queue drained.
+func (o *output) wait(timeout time.Duration) bool {
+	timer := time.AfterFunc(timeout, func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		o.idle.Broadcast()
+	})
+	defer timer.Stop()
+
+	deadline := time.Now().Add(timeout)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for (len(o.queue) > 0 || o.busy) && !o.stopped && time.Now().Before(deadline) {
+		o.idle.Wait()
+	}
+	return len(o.queue) == 0 && !o.busy
+}
+
+// stop drops the queued messages and ends the goroutine once the write in
+// progress, if any, returns.
+func (o *output) stop() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.stopped {
+		o.stopped = true
+		o.queue = nil
+		close(o.wake)
+		o.idle.Broadcast()
+	}
+}
+
+func (o *output) run() {
+	defer func() {
+		if o.port != nil {
+			o.port.Close()
+		}
+	}()
+	for range o.wake {
+		for {
+			o.mu.Lock()
+			if o.stopped || len(o.queue) == 0 {
+				o.busy = false
+				o.idle.Broadcast()
+				o.mu.Unlock()
+				break
+			}
+			msg := o.queue[0]
+			o.queue = o.queue[1:]
+			o.busy = true
+			o.mu.Unlock()
+
+			o.sending.Store(time.Now().UnixNano())
+			err := o.send(msg)
+			o.sending.Store(0)
+
+			if err != nil {
+				o.mu.Lock()
+				if o.err == nil {
+					o.err = err
+				}
+				o.mu.Unlock()
+			}
+		}
+	}
+}
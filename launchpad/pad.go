@@ -0,0 +1,77 @@
+package launchpad
+
+// Pad is the state of a single LED on the device, either a grid pad or
+// one of the round control buttons.
+type Pad struct {
+	Pos   PadPos
+	Color uint8
+	// FlashColor is the colour a Blinking pad alternates with. It is off
+	// unless set and ignored in the other modes.
+	FlashColor uint8
+	LightMode  LightMode
+}
+
+// PadPos addresses a pad the way the Launchpad does in programmer mode:
+// rows and columns count from 1 at the bottom left, row 9 is the top
+// button row and column 9 the right button column.
+type PadPos struct {
+	Row uint8
+	Col uint8
+}
+
+func NewPad(pos PadPos) Pad {
+	return Pad{
+		Pos:       pos,
+		Color:     0,
+		LightMode: Permanent,
+	}
+}
+
+// Key returns the note or controller number the pad is addressed by.
+func (p PadPos) Key() uint8 {
+	return p.Row*10 + p.Col
+}
+
+// IsButton reports whether the position is one of the round control
+// buttons along the top row or right column rather than a grid pad.
+func (p PadPos) IsButton() bool {
+	return p.Col > 8 || p.Row > 8
+}
+
+// Arrow buttons at the left end of the top row.
+var (
+	ButtonUp    = PadPos{9, 1}
+	ButtonDown  = PadPos{9, 2}
+	ButtonLeft  = PadPos{9, 3}
+	ButtonRight = PadPos{9, 4}
+)
+
+func PadPosFromKey(key uint8) PadPos {
+	return PadPos{uint8(key / 10), uint8(key % 10)}
+}
+
+// LightMode selects how the Launchpad renders a pad colour. The device
+// picks the mode from the MIDI channel the colour arrives on: static on
+// channel 1, flashing on channel 2 and pulsing on channel 3.
+type LightMode uint8
+
+const (
+	Permanent LightMode = iota
+	Blinking
+	Pulsing
+)
+
+// channel returns the zero based MIDI channel that selects the mode.
+func (m LightMode) channel() uint8 {
+	switch m {
+	case Blinking:
+		return 1
+	case Pulsing:
+		return 2
+	default:
+		return 0
+	}
+}
+
+const On = true
+const Off = false
@@ -0,0 +1,120 @@
+package launchpad
+
+import "time"
+
+const (
+	// safeFlashInterval is the shortest time between two large changes in
+	// safe mode, keeping full-grid flashes to three a second.
+	safeFlashInterval = time.Second / 3
+	// safeLargeChange is how many grid pads have to change at once for a
+	// frame to count as a flash.
+	safeLargeChange = 32
+	// safeBrightness is the LED brightness, out of 127, in safe mode.
+	safeBrightness = 80
+)
+
+// safeMode is the state of the photosensitivity limiter.
+type safeMode struct {
+	on        bool
+	lastFlash time.Time
+	pending   *Frame
+}
+
+// SetSafeMode turns the photosensitivity limiter on or off. With it on,
+// frames that change half the grid or more are shown at most three times a
+// second; a frame arriving sooner is held back and shown late unless a
+// newer frame replaces it. Flashing pads pulse instead, which fades rather
+// than strobes, and the LEDs are dimmed to cap the intensity of a flash.
+// The limits apply to every way of lighting pads.
+//
+// Brightness is only sent when safe mode changes: turning it off restores
+// full brightness, and a device that was never in safe mode keeps the
+// brightness set on the hardware.
+func (d *Device) SetSafeMode(on bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if on == d.safe.on {
+		return nil
+	}
+	d.safe.on = on
+	d.safe.pending = nil
+	return d.write(brightness(on))
+}
+
+// brightness encodes the LED brightness message (command 0x08) for the
+// safe mode setting.
+func brightness(safe bool) []byte {
+	level := byte(127)
+	if safe {
+		level = safeBrightness
+	}
+	return []byte{0xF0, 0x00, 0x20, 0x29, 0x02, 0x0D, 0x08, level, 0xF7}
+}
+
+// renderSafe renders f under the safe mode limits. The caller holds d.mu.
+func (d *Device) renderSafe(f Frame) error {
+	f = soften(f)
+	if changedGridPads(d.frame, f) >= safeLargeChange {
+		if wait := safeFlashInterval - time.Since(d.safe.lastFlash); wait > 0 {
+			if d.safe.pending == nil {
+				time.AfterFunc(wait, d.flushPending)
+			}
+			d.safe.pending = &f
+			return nil
+		}
+		d.safe.lastFlash = time.Now()
+	}
+	d.safe.pending = nil
+	return d.render(f, false)
+}
+
+// renderSafePads renders pads on top of the newest frame under the safe
+// mode limits. The caller holds d.mu.
+func (d *Device) renderSafePads(pads []Pad) error {
+	f := d.frame
+	if d.safe.pending != nil {
+		f = *d.safe.pending
+	}
+	for _, pad := range pads {
+		f.SetPad(pad)
+	}
+	return d.renderSafe(f)
+}
+
+// flushPending shows a frame held back by renderSafe.
+func (d *Device) flushPending() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.safe.pending == nil {
+		return
+	}
+	f := *d.safe.pending
+	d.safe.pending = nil
+	d.safe.lastFlash = time.Now()
+	d.render(f, false)
+}
+
+// soften returns f with flashing pads turned into pulsing ones.
+func soften(f Frame) Frame {
+	for r := range f.cells {
+		for c := range f.cells[r] {
+			if f.cells[r][c].lightMode == Blinking {
+				f.cells[r][c] = cell{color: f.cells[r][c].color, lightMode: Pulsing}
+			}
+		}
+	}
+	return f
+}
+
+// changedGridPads counts the grid pads that differ between a and b.
+func changedGridPads(a, b Frame) int {
+	n := 0
+	for r := range 8 {
+		for c := range 8 {
+			if a.cells[r][c] != b.cells[r][c] {
+				n++
+			}
+		}
+	}
+	return n
+}
@@ -0,0 +1,98 @@
+package launchpad
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSafeModeBrightness(t *testing.T) {
+	tests := []struct {
+		name string
+		set  []bool
+		want []string
+	}{
+		{"never on", []bool{false}, nil},
+		{"on", []bool{true, true}, []string{"f0002029020d0850f7"}},
+		{"on then off", []bool{true, false, false}, []string{"f0002029020d0850f7", "f0002029020d087ff7"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r recorder
+			d := New(r.send)
+			for _, on := range tt.set {
+				d.SetSafeMode(on)
+			}
+			if sent := r.written(t, d); fmt.Sprint(sent) != fmt.Sprint(tt.want) {
+				t.Errorf("sent %v, want %v", sent, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafeModeSoftensFlashing(t *testing.T) {
+	pos := PadPos{Row: 1, Col: 1}
+	tests := []struct {
+		name string
+		draw func(d *Device)
+	}{
+		{"Render", func(d *Device) {
+			var f Frame
+			f.SetFlash(pos, ColorRed, ColorWhite)
+			d.Render(f)
+		}},
+		{"SendNote", func(d *Device) {
+			d.SendNote(On, Pad{Pos: pos, Color: ColorRed, FlashColor: ColorWhite, LightMode: Blinking})
+		}},
+		{"SetPadFlash", func(d *Device) { d.SetPadFlash(pos, ColorRed, ColorWhite) }},
+		{"FlushBatch", func(d *Device) {
+			d.FlushBatch([]Pad{{Pos: pos, Color: ColorRed, FlashColor: ColorWhite, LightMode: Blinking}})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r recorder
+			d := New(r.send)
+			d.SetSafeMode(true)
+			tt.draw(d)
+			if got := d.Pad(pos); got.LightMode != Pulsing || got.Color != ColorRed {
+				t.Errorf("pad is %+v, want pulsing red", got)
+			}
+			if sent := r.written(t, d); len(sent) != 2 || sent[1] != "f0002029020d03020b05f7" {
+				t.Errorf("sent %v", sent)
+			}
+		})
+	}
+}
+
+func TestSafeModeHoldsBackFlashes(t *testing.T) {
+	full := func(color uint8) []Pad {
+		var pads []Pad
+		for r := range uint8(8) {
+			for c := range uint8(8) {
+				pads = append(pads, Pad{Pos: PadPos{Row: r + 1, Col: c + 1}, Color: color})
+			}
+		}
+		return pads
+	}
+	var r recorder
+	d := New(r.send)
+	d.SetSafeMode(true)
+
+	d.FlushBatch(full(ColorRed))
+	d.FlushBatch(full(ColorWhite))
+	if got := d.Pad(PadPos{Row: 1, Col: 1}).Color; got != ColorRed {
+		t.Fatalf("second flash shown at once, pad is %d", got)
+	}
+	if sent := r.written(t, d); len(sent) != 2 {
+		t.Fatalf("sent %d messages, want brightness and one frame", len(sent))
+	}
+
+	time.Sleep(safeFlashInterval + 50*time.Millisecond)
+	if got := d.Pad(PadPos{Row: 1, Col: 1}).Color; got != ColorWhite {
+		t.Errorf("held back flash not shown, pad is %d", got)
+	}
+	if sent := r.written(t, d); len(sent) != 3 {
+		t.Errorf("sent %d messages, want the held back frame too", len(sent))
+	}
+}
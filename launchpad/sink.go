@@ -0,0 +1,113 @@
+package launchpad
+
+import (
+	"sync"
+	"time"
+)
+
+// Sink receives rendered frames. Device is a Sink; mirrors, recorders and
+// bridges to other lights can be others.
+type Sink interface {
+	Render(f Frame) error
+}
+
+// Fanout renders every frame to several sinks. Each sink is fed by its own
+// goroutine and may set a minimum interval between frames. A sink that
+// falls behind skips to the newest frame, so a slow sink never holds up
+// the device or the caller.
+type Fanout struct {
+	mu      sync.Mutex
+	workers []*sinkWorker
+}
+
+// Add starts feeding frames to sink, at most one per interval. An
+// interval of zero passes on every frame the sink keeps up with. failed,
+// if not nil, is called with every error the sink returns, on the
+// goroutine feeding it.
+func (f *Fanout) Add(sink Sink, interval time.Duration, failed func(err error)) {
+	w := &sinkWorker{
+		sink:     sink,
+		interval: interval,
+		failed:   failed,
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	w.wg.Add(1)
+	go w.run()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.workers = append(f.workers, w)
+}
+
+// Render hands frame to every sink and returns without waiting for them.
+// Errors of the sinks go to the callbacks given to Add.
+func (f *Fanout) Render(frame Frame) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, w := range f.workers {
+		w.post(frame)
+	}
+	return nil
+}
+
+// Close stops feeding the sinks, waiting for frames being rendered.
+// Frames not yet rendered are dropped.
+func (f *Fanout) Close() {
+	f.mu.Lock()
+	workers := f.workers
+	f.workers = nil
+	f.mu.Unlock()
+	for _, w := range workers {
+		close(w.done)
+		w.wg.Wait()
+	}
+}
+
+// sinkWorker holds the newest frame for one sink.
+type sinkWorker struct {
+	sink     Sink
+	interval time.Duration
+	failed   func(err error)
+	wake     chan struct{}
+	done     chan struct{}
+	wg       sync.WaitGroup
+
+	mu    sync.Mutex
+	frame Frame
+}
+
+func (w *sinkWorker) post(frame Frame) {
+	w.mu.Lock()
+	w.frame = frame
+	w.mu.Unlock()
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *sinkWorker) run() {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.wake:
+		}
+		w.mu.Lock()
+		frame := w.frame
+		w.mu.Unlock()
+		if err := w.sink.Render(frame); err != nil && w.failed != nil {
+			w.failed(err)
+		}
+
+		if w.interval > 0 {
+			select {
+			case <-w.done:
+				return
+			case <-time.After(w.interval):
+			}
+		}
+	}
+}
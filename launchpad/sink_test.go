@@ -0,0 +1,108 @@
+package launchpad
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowSink takes delay to render a frame and records the colour of pad
+// 1,1 of every frame it rendered along with the time it started.
+type slowSink struct {
+	delay time.Duration
+	err   error
+
+	mu     sync.Mutex
+	colors []uint8
+	times  []time.Time
+}
+
+func (s *slowSink) Render(f Frame) error {
+	s.mu.Lock()
+	s.colors = append(s.colors, f.Pad(PadPos{Row: 1, Col: 1}).Color)
+	s.times = append(s.times, time.Now())
+	s.mu.Unlock()
+	time.Sleep(s.delay)
+	return s.err
+}
+
+func (s *slowSink) rendered() ([]uint8, []time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]uint8(nil), s.colors...), append([]time.Time(nil), s.times...)
+}
+
+func frameOf(color uint8) Frame {
+	var f Frame
+	f.Set(PadPos{Row: 1, Col: 1}, color)
+	return f
+}
+
+func TestFanoutSkipsToNewest(t *testing.T) {
+	sink := &slowSink{delay: 50 * time.Millisecond}
+	var fan Fanout
+	fan.Add(sink, 0, nil)
+
+	fan.Render(frameOf(1))
+	time.Sleep(10 * time.Millisecond)
+	for color := uint8(2); color <= 5; color++ {
+		fan.Render(frameOf(color))
+	}
+	time.Sleep(150 * time.Millisecond)
+	fan.Close()
+
+	colors, _ := sink.rendered()
+	if len(colors) != 2 || colors[0] != 1 || colors[1] != 5 {
+		t.Errorf("rendered %v, want [1 5]", colors)
+	}
+}
+
+func TestFanoutInterval(t *testing.T) {
+	const interval = 40 * time.Millisecond
+	slow, fast := &slowSink{}, &slowSink{}
+	var fan Fanout
+	fan.Add(slow, interval, nil)
+	fan.Add(fast, 0, nil)
+
+	for color := uint8(1); color <= 10; color++ {
+		fan.Render(frameOf(color))
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(2 * interval)
+	fan.Close()
+
+	colors, times := slow.rendered()
+	if len(colors) < 2 || len(colors) > 5 {
+		t.Fatalf("rate limited sink rendered %d frames, want 2 to 5", len(colors))
+	}
+	for i := 1; i < len(times); i++ {
+		if gap := times[i].Sub(times[i-1]); gap < interval {
+			t.Errorf("frames %v apart, want at least %v", gap, interval)
+		}
+	}
+	if colors[len(colors)-1] != 10 {
+		t.Errorf("rate limited sink ended on frame %d, want the newest", colors[len(colors)-1])
+	}
+	if fastColors, _ := fast.rendered(); len(fastColors) <= len(colors) {
+		t.Errorf("unlimited sink rendered %d frames, no more than the limited one", len(fastColors))
+	}
+}
+
+func TestFanoutReportsErrors(t *testing.T) {
+	errSink := errors.New("sink failed")
+	failed := make(chan error, 1)
+	var fan Fanout
+	fan.Add(&slowSink{err: errSink}, 0, func(err error) { failed <- err })
+	defer fan.Close()
+
+	fan.Render(frameOf(1))
+	select {
+	case err := <-failed:
+		if err != errSink {
+			t.Errorf("got error %v, want %v", err, errSink)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("sink error not reported")
+	}
+}
@@ -0,0 +1,87 @@
+package launchpad
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/codeneuss/LaunchPadStreamer/events"
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Watchdog checks the output to the device until the returned function is
+// called. When a write has been blocked for longer than timeout, it
+// abandons the output port, opens the port afresh, puts the device back
+// into programmer mode and sends the whole frame again. The blocked port
+// is closed once its write returns. Each blocked write is recovered from
+// once; recovered, if not nil, is called after every recovery with its
+// result.
+//
+// Devices created with New have no port to reopen; for those the frame is
+// resent once the blocked write returns.
+//
+// Only a blocked driver is detected. A game that stops drawing because
+// one of its goroutines deadlocked looks the same to the device as one
+// showing a still frame.
+func (d *Device) Watchdog(timeout time.Duration, recovered func(err error)) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(timeout / 2)
+		defer ticker.Stop()
+		var handled int64
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			d.mu.Lock()
+			started := d.out.stalled(timeout)
+			d.mu.Unlock()
+			if started == 0 || started == handled {
+				continue
+			}
+			handled = started
+			err := d.reset()
+			events.Publish(events.DeviceReconnected{Err: err})
+			if recovered != nil {
+				recovered(err)
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// reset replaces a blocked output and queues the current frame. The new
+// port is opened before d.mu is taken, so a driver that blocks again
+// holds up only the watchdog, not the callers lighting pads.
+func (d *Device) reset() error {
+	d.mu.Lock()
+	reopen := d.out.port != nil
+	d.mu.Unlock()
+
+	var out *output
+	if reopen {
+		port, err := midi.FindOutPort(OutPortName)
+		if err != nil {
+			return fmt.Errorf("reopening output: %w", err)
+		}
+		send, err := midi.SendTo(port)
+		if err != nil {
+			port.Close()
+			return fmt.Errorf("reopening output: %w", err)
+		}
+		out = newOutput(send, port)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if out != nil {
+		d.out.stop()
+		d.out = out
+		d.write(programmerMode)
+		if d.safe.on {
+			d.write(brightness(true))
+		}
+	}
+	return d.render(d.frame, true)
+}
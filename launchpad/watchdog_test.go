@@ -0,0 +1,60 @@
+package launchpad
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// A blocked write is recovered from once, however long it stays blocked.
+func TestWatchdogRecoversOnce(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		sent  int
+		block = make(chan struct{})
+	)
+	d := New(func(midi.Message) error {
+		mu.Lock()
+		sent++
+		first := sent == 1
+		mu.Unlock()
+		if first {
+			<-block
+		}
+		return nil
+	})
+
+	var f Frame
+	f.Set(PadPos{Row: 1, Col: 1}, ColorRed)
+	if err := d.Render(f); err != nil {
+		t.Fatal(err)
+	}
+
+	var recoveries int
+	stop := d.Watchdog(10*time.Millisecond, func(err error) {
+		if err != nil {
+			t.Errorf("recovery failed: %v", err)
+		}
+		mu.Lock()
+		recoveries++
+		mu.Unlock()
+	})
+	time.Sleep(100 * time.Millisecond)
+	close(block)
+	if !d.out.wait(time.Second) {
+		t.Fatal("output did not drain")
+	}
+	stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if recoveries != 1 {
+		t.Errorf("recovered %d times, want 1", recoveries)
+	}
+	// The blocked frame and the one resent by the recovery.
+	if sent != 2 {
+		t.Errorf("sent %d messages, want 2", sent)
+	}
+}
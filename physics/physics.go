@@ -0,0 +1,119 @@
+// Package physics moves bodies over the pad grid in continuous
+// coordinates: velocity integration, gravity, bouncing off the bounds of an
+// area and off occupied cells.
+//
+// Coordinates are in pads and match launchpad.PadPos: X is the column and
+// Y the row, so the pad {Row: 2, Col: 5} is centred on X 5, Y 2.
+package physics
+
+import (
+	"math"
+
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+// Body is a point with a velocity in pads per second.
+type Body struct {
+	X, Y   float64
+	VX, VY float64
+}
+
+// Pos returns the pad the body is on. Positions left of or below the grid
+// map to column or row 0.
+func (b Body) Pos() launchpad.PadPos {
+	return launchpad.PadPos{Row: cell(b.Y), Col: cell(b.X)}
+}
+
+func cell(v float64) uint8 {
+	return uint8(min(max(math.Round(v), 0), 255))
+}
+
+// Rect is an area bodies are kept inside, inclusive of its edges.
+type Rect struct {
+	MinX, MinY float64
+	MaxX, MaxY float64
+}
+
+// Grid is the 8x8 pad grid.
+var Grid = Rect{MinX: 1, MinY: 1, MaxX: 8, MaxY: 8}
+
+// Side is a set of sides of a Rect.
+type Side uint8
+
+const (
+	Left Side = 1 << iota
+	Right
+	Bottom
+	Top
+)
+
+// World holds the rules bodies move by.
+type World struct {
+	// Bounds is the area bodies bounce inside.
+	Bounds Rect
+	// Gravity is added to VY every second; negative pulls towards row 1.
+	Gravity float64
+	// Restitution scales the speed after a bounce. Zero is taken as 1,
+	// which keeps all of it.
+	Restitution float64
+	// Solid reports whether a pad blocks bodies. It may be nil.
+	Solid func(pos launchpad.PadPos) bool
+}
+
+// Collision reports what a body bounced off during a Step.
+type Collision struct {
+	Walls Side
+	Cells []launchpad.PadPos
+}
+
+// Step advances b by dt seconds and bounces it off solid cells and the
+// bounds.
+func (w World) Step(b *Body, dt float64) Collision {
+	var hit Collision
+	b.VY += w.Gravity * dt
+
+	x, y := b.X+b.VX*dt, b.Y+b.VY*dt
+	if w.Solid != nil {
+		from := b.Pos()
+		to := Body{X: x, Y: y}.Pos()
+		if to != from && w.Solid(to) {
+			hit.Cells = append(hit.Cells, to)
+			// Bounce back on every axis that crossed into the cell.
+			if to.Col != from.Col {
+				b.VX = -b.VX * w.restitution()
+				x = b.X
+			}
+			if to.Row != from.Row {
+				b.VY = -b.VY * w.restitution()
+				y = b.Y
+			}
+		}
+	}
+	b.X, b.Y = x, y
+
+	if b.X < w.Bounds.MinX {
+		b.X, b.VX = 2*w.Bounds.MinX-b.X, -b.VX*w.restitution()
+		hit.Walls |= Left
+	} else if b.X > w.Bounds.MaxX {
+		b.X, b.VX = 2*w.Bounds.MaxX-b.X, -b.VX*w.restitution()
+		hit.Walls |= Right
+	}
+	if b.Y < w.Bounds.MinY {
+		b.Y, b.VY = 2*w.Bounds.MinY-b.Y, -b.VY*w.restitution()
+		hit.Walls |= Bottom
+	} else if b.Y > w.Bounds.MaxY {
+		b.Y, b.VY = 2*w.Bounds.MaxY-b.Y, -b.VY*w.restitution()
+		hit.Walls |= Top
+	}
+	// A fast body can overshoot the opposite edge when reflected.
+	b.X = min(max(b.X, w.Bounds.MinX), w.Bounds.MaxX)
+	b.Y = min(max(b.Y, w.Bounds.MinY), w.Bounds.MaxY)
+	return hit
+}
+
+func (w World) restitution() float64 {
+	if w.Restitution == 0 {
+		return 1
+	}
+	return w.Restitution
+}
@@ -0,0 +1,168 @@
+package physics
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/codeneuss/LaunchPadStreamer/launchpad"
+)
+
+func TestStep(t *testing.T) {
+	solid := func(cells ...launchpad.PadPos) func(launchpad.PadPos) bool {
+		return func(pos launchpad.PadPos) bool { return slices.Contains(cells, pos) }
+	}
+	tests := []struct {
+		name  string
+		world World
+		body  Body
+		dt    float64
+		want  Body
+		walls Side
+		cells []launchpad.PadPos
+	}{
+		{
+			name:  "free flight",
+			world: World{Bounds: Grid},
+			body:  Body{X: 4, Y: 4, VX: 1, VY: -2},
+			dt:    0.5,
+			want:  Body{X: 4.5, Y: 3, VX: 1, VY: -2},
+		},
+		{
+			name:  "left wall",
+			world: World{Bounds: Grid},
+			body:  Body{X: 1.1, Y: 4, VX: -1},
+			dt:    0.2,
+			want:  Body{X: 1.1, Y: 4, VX: 1},
+			walls: Left,
+		},
+		{
+			name:  "right wall",
+			world: World{Bounds: Grid},
+			body:  Body{X: 7.9, Y: 4, VX: 1},
+			dt:    0.2,
+			want:  Body{X: 7.9, Y: 4, VX: -1},
+			walls: Right,
+		},
+		{
+			name:  "bottom wall",
+			world: World{Bounds: Grid},
+			body:  Body{X: 4, Y: 1.1, VY: -1},
+			dt:    0.2,
+			want:  Body{X: 4, Y: 1.1, VY: 1},
+			walls: Bottom,
+		},
+		{
+			name:  "top wall",
+			world: World{Bounds: Grid},
+			body:  Body{X: 4, Y: 7.9, VY: 1},
+			dt:    0.2,
+			want:  Body{X: 4, Y: 7.9, VY: -1},
+			walls: Top,
+		},
+		{
+			name:  "corner",
+			world: World{Bounds: Grid},
+			body:  Body{X: 7.9, Y: 7.9, VX: 1, VY: 1},
+			dt:    0.2,
+			want:  Body{X: 7.9, Y: 7.9, VX: -1, VY: -1},
+			walls: Right | Top,
+		},
+		{
+			name:  "gravity",
+			world: World{Bounds: Grid, Gravity: -10},
+			body:  Body{X: 4, Y: 4, VY: 1},
+			dt:    0.1,
+			want:  Body{X: 4, Y: 4, VY: 0},
+		},
+		{
+			name:  "gravity from rest",
+			world: World{Bounds: Grid, Gravity: -10},
+			body:  Body{X: 4, Y: 4},
+			dt:    0.1,
+			want:  Body{X: 4, Y: 3.9, VY: -1},
+		},
+		{
+			name:  "restitution",
+			world: World{Bounds: Grid, Restitution: 0.5},
+			body:  Body{X: 1.1, Y: 4, VX: -1},
+			dt:    0.2,
+			want:  Body{X: 1.1, Y: 4, VX: 0.5},
+			walls: Left,
+		},
+		{
+			name:  "solid cell across columns",
+			world: World{Bounds: Grid, Solid: solid(launchpad.PadPos{Row: 4, Col: 5})},
+			body:  Body{X: 4, Y: 4, VX: 10},
+			dt:    0.1,
+			want:  Body{X: 4, Y: 4, VX: -10},
+			cells: []launchpad.PadPos{{Row: 4, Col: 5}},
+		},
+		{
+			name:  "solid cell across rows",
+			world: World{Bounds: Grid, Solid: solid(launchpad.PadPos{Row: 5, Col: 4})},
+			body:  Body{X: 4, Y: 4, VX: 1, VY: 10},
+			dt:    0.1,
+			want:  Body{X: 4.1, Y: 4, VX: 1, VY: -10},
+			cells: []launchpad.PadPos{{Row: 5, Col: 4}},
+		},
+		{
+			name:  "solid cell diagonally",
+			world: World{Bounds: Grid, Solid: solid(launchpad.PadPos{Row: 5, Col: 5}), Restitution: 0.5},
+			body:  Body{X: 4, Y: 4, VX: 10, VY: 10},
+			dt:    0.1,
+			want:  Body{X: 4, Y: 4, VX: -5, VY: -5},
+			cells: []launchpad.PadPos{{Row: 5, Col: 5}},
+		},
+		{
+			name:  "moving inside a solid cell",
+			world: World{Bounds: Grid, Solid: solid(launchpad.PadPos{Row: 4, Col: 4})},
+			body:  Body{X: 4, Y: 4, VX: 1},
+			dt:    0.1,
+			want:  Body{X: 4.1, Y: 4, VX: 1},
+		},
+		{
+			name:  "overshoot is clamped",
+			world: World{Bounds: Grid},
+			body:  Body{X: 7.5, Y: 4, VX: 100},
+			dt:    0.1,
+			want:  Body{X: 1, Y: 4, VX: -100},
+			walls: Right,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.body
+			hit := tt.world.Step(&b, tt.dt)
+			if !near(b.X, tt.want.X) || !near(b.Y, tt.want.Y) || !near(b.VX, tt.want.VX) || !near(b.VY, tt.want.VY) {
+				t.Errorf("body %+v, want %+v", b, tt.want)
+			}
+			if hit.Walls != tt.walls {
+				t.Errorf("walls %04b, want %04b", hit.Walls, tt.walls)
+			}
+			if !slices.Equal(hit.Cells, tt.cells) {
+				t.Errorf("cells %v, want %v", hit.Cells, tt.cells)
+			}
+		})
+	}
+}
+
+func TestPos(t *testing.T) {
+	tests := []struct {
+		body Body
+		want launchpad.PadPos
+	}{
+		{Body{X: 1, Y: 1}, launchpad.PadPos{Row: 1, Col: 1}},
+		{Body{X: 4.4, Y: 2.6}, launchpad.PadPos{Row: 3, Col: 4}},
+		{Body{X: -3, Y: 300}, launchpad.PadPos{Row: 255, Col: 0}},
+	}
+	for _, tt := range tests {
+		if got := tt.body.Pos(); got != tt.want {
+			t.Errorf("%+v.Pos() = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}